@@ -0,0 +1,428 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/loader"
+	"github.com/SliderVM/FIASParse/fias/query"
+	"github.com/SliderVM/FIASParse/fias/source"
+	"github.com/spf13/viper"
+)
+
+var workRegimePattern = regexp.MustCompile("^[01]{4}$")
+
+// Config - настройки загрузчика
+type Config struct {
+	Database DatabaseConfig
+	Service  source.Config
+
+	Validation ValidationConfig
+	Sanity     SanityConfig
+	Sink       SinkConfig
+	Events     EventsConfig
+
+	// Diff - журнал изменений <таблица>_changes при каждой загрузке
+	Diff bool
+	// Derived - производные таблицы, пересчитываемые после загрузки
+	Derived []string
+
+	// FormatTemplates - шаблоны форматирования адреса в дополнение к query.Templates
+	FormatTemplates map[string]query.Template
+
+	// APIListen - адрес HTTP API запросов к реестру, пусто - API выключен
+	APIListen string
+
+	// KeepVersions - сколько предыдущих версий таблиц хранить для отката
+	KeepVersions int
+
+	DirName    string
+	FileName   string
+	WorkRegime string
+	Source     string
+
+	OnDecodeError     string
+	QuarantineStorage string
+	QuarantineDir     string
+	Schemas           bool
+	FailOnUnknown     bool
+
+	LogLevel         string
+	LogFormat        string
+	Interactive      bool
+	ProgressInterval time.Duration
+}
+
+// DatabaseConfig - настройки подключения к БД
+type DatabaseConfig struct {
+	Server       string
+	Port         int
+	User         string
+	Password     string
+	PasswordFile string
+	Base         string
+	SSLMode      string
+	DSN          string
+}
+
+// ValidationConfig - настройки проверки целостности после загрузки
+type ValidationConfig struct {
+	// Enabled - по умолчанию выключена: каждая проверка читает таблицу целиком
+	Enabled bool
+	Fail    bool
+	Sample  int
+}
+
+// SanityConfig - пороги, при нарушении которых новая версия таблицы не подменяет текущую
+type SanityConfig struct {
+	MaxDropPercent float64
+	Tables         map[string]float64
+}
+
+// SinkConfig - приемники записей
+type SinkConfig struct {
+	Types     []string
+	ExportDir string
+}
+
+// EventsConfig - отправка событий по журналу изменений в брокер после загрузки
+type EventsConfig struct {
+	Enabled       bool
+	Broker        string // nats, kafka или file
+	URL           string // адрес NATS, брокеры Kafka через запятую или путь к файлу ("-" - stdout)
+	Topic         string // топик Kafka или префикс темы NATS
+	IncludeRecord bool
+}
+
+// templateConfig - шаблон форматирования адреса из секции [format.templates.<имя>]
+type templateConfig struct {
+	Order      string `mapstructure:"order"`
+	Names      string `mapstructure:"names"`
+	PostalCode bool   `mapstructure:"postal_code"`
+	Region     bool   `mapstructure:"region"`
+	Separator  string `mapstructure:"separator"`
+}
+
+// loadConfig - читаем конфиг из файла (или каталога) path и переменных окружения FIAS_*
+func loadConfig(path string) (*Config, error) {
+	viper.SetDefault("database.sslmode", "disable")
+	viper.SetDefault("config.dir_name", "/FIAS/")
+	viper.SetDefault("config.file_name", "fias.rar")
+	viper.SetDefault("config.work_regime", "1111")
+	viper.SetDefault("service.url", source.DefaultServiceURL)
+	viper.SetDefault("service.timeout", "1m")
+	viper.SetDefault("service.connect_timeout", "30s")
+	viper.SetDefault("service.download_timeout", "0s")
+	viper.SetDefault("service.user_agent", "FIASParse")
+	viper.SetDefault("validate.enabled", false)
+	viper.SetDefault("validate.fail", false)
+	viper.SetDefault("validate.sample", 10)
+	viper.SetDefault("sanity.max_drop_percent", 20)
+	viper.SetDefault("diff.enabled", false)
+	viper.SetDefault("snapshots.keep", 0)
+	viper.SetDefault("sink.types", []string{"postgres"})
+	viper.SetDefault("sink.export_dir", "export")
+	viper.SetDefault("events.broker", "nats")
+	viper.SetDefault("events.url", "nats://127.0.0.1:4222")
+	viper.SetDefault("events.topic", "fias")
+	viper.SetDefault("parse.on_error", loader.PolicyAbort)
+	viper.SetDefault("parse.quarantine", loader.QuarantineFile)
+	viper.SetDefault("parse.quarantine_dir", "quarantine")
+	viper.SetDefault("parse.schemas", true)
+	viper.SetDefault("parse.fail_on_unknown", false)
+	viper.SetDefault("log.level", "info")
+	viper.SetDefault("log.format", "text")
+	viper.SetDefault("log.interactive", true)
+	viper.SetDefault("log.progress_interval", "30s")
+
+	viper.SetEnvPrefix("FIAS")
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
+	if fi, err := os.Stat(path); path != "" && err == nil && !fi.IsDir() {
+		viper.SetConfigFile(path)
+	} else {
+		viper.SetConfigName("config")
+		if path != "" {
+			viper.AddConfigPath(path)
+		}
+		viper.AddConfigPath(".")
+		viper.AddConfigPath("./config")
+	}
+
+	if err := viper.ReadInConfig(); err != nil {
+		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
+			return nil, fmt.Errorf("чтение конфига: %w", err)
+		}
+	}
+
+	cfg := &Config{
+		Database: DatabaseConfig{
+			Server:       databaseString("server"),
+			Port:         databaseInt("port", 5432),
+			User:         databaseString("user"),
+			Password:     databaseString("password"),
+			PasswordFile: databaseString("password_file"),
+			Base:         databaseString("base"),
+			SSLMode:      viper.GetString("database.sslmode"),
+			DSN:          viper.GetString("database.dsn"),
+		},
+		Service: source.Config{
+			URL:             viper.GetString("service.url"),
+			Timeout:         viper.GetDuration("service.timeout"),
+			ConnectTimeout:  viper.GetDuration("service.connect_timeout"),
+			DownloadTimeout: viper.GetDuration("service.download_timeout"),
+			Proxy:           viper.GetString("service.proxy"),
+			CAFile:          viper.GetString("service.ca_file"),
+			UserAgent:       viper.GetString("service.user_agent"),
+		},
+		Validation: ValidationConfig{
+			Enabled: viper.GetBool("validate.enabled"),
+			Fail:    viper.GetBool("validate.fail"),
+			Sample:  viper.GetInt("validate.sample"),
+		},
+		Sanity: SanityConfig{
+			MaxDropPercent: viper.GetFloat64("sanity.max_drop_percent"),
+			Tables:         map[string]float64{},
+		},
+		Sink: SinkConfig{
+			Types:     viper.GetStringSlice("sink.types"),
+			ExportDir: viper.GetString("sink.export_dir"),
+		},
+		Events: EventsConfig{
+			Enabled:       viper.GetBool("events.enabled"),
+			Broker:        viper.GetString("events.broker"),
+			URL:           viper.GetString("events.url"),
+			Topic:         viper.GetString("events.topic"),
+			IncludeRecord: viper.GetBool("events.include_record"),
+		},
+		Diff:              viper.GetBool("diff.enabled"),
+		KeepVersions:      viper.GetInt("snapshots.keep"),
+		APIListen:         viper.GetString("api.listen"),
+		Derived:           viper.GetStringSlice("derived.tables"),
+		DirName:           viper.GetString("config.dir_name"),
+		FileName:          viper.GetString("config.file_name"),
+		WorkRegime:        viper.GetString("config.work_regime"),
+		Source:            viper.GetString("config.source"),
+		OnDecodeError:     viper.GetString("parse.on_error"),
+		QuarantineStorage: viper.GetString("parse.quarantine"),
+		QuarantineDir:     viper.GetString("parse.quarantine_dir"),
+		Schemas:           viper.GetBool("parse.schemas"),
+		FailOnUnknown:     viper.GetBool("parse.fail_on_unknown"),
+		LogLevel:          viper.GetString("log.level"),
+		LogFormat:         viper.GetString("log.format"),
+		Interactive:       viper.GetBool("log.interactive"),
+		ProgressInterval:  viper.GetDuration("log.progress_interval"),
+	}
+
+	for table, value := range viper.GetStringMapString("sanity.tables") {
+		percent, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("sanity.tables.%s: %w", table, err)
+		}
+		cfg.Sanity.Tables[table] = percent
+	}
+
+	var templates map[string]templateConfig
+	if err := viper.UnmarshalKey("format.templates", &templates); err != nil {
+		return nil, fmt.Errorf("format.templates: %w", err)
+	}
+	cfg.FormatTemplates = map[string]query.Template{}
+	for name, t := range templates {
+		template := query.Template{Order: t.Order, Names: t.Names, PostalCode: t.PostalCode, Region: t.Region, Separator: t.Separator}
+		if template.Order == "" {
+			template.Order = query.OrderAsc
+		}
+		if template.Names == "" {
+			template.Names = query.NamesShort
+		}
+		cfg.FormatTemplates[name] = template
+	}
+
+	if cfg.Database.PasswordFile != "" {
+		password, err := os.ReadFile(cfg.Database.PasswordFile)
+		if err != nil {
+			return nil, fmt.Errorf("чтение файла пароля: %w", err)
+		}
+		cfg.Database.Password = strings.TrimRight(string(password), "\r\n")
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// databaseString - значение из секции [database], для старых конфигов - из [datebase]
+func databaseString(key string) string {
+	if v := viper.GetString("database." + key); v != "" {
+		return v
+	}
+	return viper.GetString("datebase." + key)
+}
+
+// databaseInt - числовое значение из секции [database] или [datebase], иначе def
+func databaseInt(key string, def int) int {
+	if v := viper.GetInt("database." + key); v != 0 {
+		return v
+	}
+	if v := viper.GetInt("datebase." + key); v != 0 {
+		return v
+	}
+	return def
+}
+
+// Validate - проверяем настройки, возвращаем все найденные ошибки
+func (c *Config) Validate() error {
+	var errs []error
+
+	if !workRegimePattern.MatchString(c.WorkRegime) {
+		errs = append(errs, fmt.Errorf("config.work_regime: ожидается 4 символа 0/1 (qwer), получено %q", c.WorkRegime))
+	}
+	if c.FileName == "" {
+		errs = append(errs, errors.New("config.file_name: не задано имя файла"))
+	}
+	if c.Database.DSN == "" {
+		if c.Database.Server == "" {
+			errs = append(errs, errors.New("database.server: не задан сервер БД"))
+		}
+		if c.Database.Base == "" {
+			errs = append(errs, errors.New("database.base: не задано имя БД"))
+		}
+		if c.Database.User == "" {
+			errs = append(errs, errors.New("database.user: не задан пользователь БД"))
+		}
+		if c.Database.Port <= 0 || c.Database.Port > 65535 {
+			errs = append(errs, fmt.Errorf("database.port: неверный порт %d", c.Database.Port))
+		}
+		switch c.Database.SSLMode {
+		case "disable", "allow", "prefer", "require", "verify-ca", "verify-full":
+		default:
+			errs = append(errs, fmt.Errorf("database.sslmode: неизвестный режим %q", c.Database.SSLMode))
+		}
+	}
+	if u, err := url.Parse(c.Service.URL); err != nil || u.Scheme == "" || u.Host == "" {
+		errs = append(errs, fmt.Errorf("service.url: неверный адрес сервиса %q", c.Service.URL))
+	}
+	if c.Service.Timeout <= 0 {
+		errs = append(errs, fmt.Errorf("service.timeout: неверный таймаут %v", c.Service.Timeout))
+	}
+	if c.Service.DownloadTimeout < 0 {
+		errs = append(errs, fmt.Errorf("service.download_timeout: неверный таймаут %v", c.Service.DownloadTimeout))
+	}
+	switch c.OnDecodeError {
+	case loader.PolicyAbort, loader.PolicySkip, loader.PolicyQuarantine:
+	default:
+		errs = append(errs, fmt.Errorf("parse.on_error: ожидается abort, skip или quarantine, получено %q", c.OnDecodeError))
+	}
+	switch c.QuarantineStorage {
+	case loader.QuarantineFile, loader.QuarantineTable:
+	default:
+		errs = append(errs, fmt.Errorf("parse.quarantine: ожидается file или table, получено %q", c.QuarantineStorage))
+	}
+	if len(c.Sink.Types) == 0 {
+		errs = append(errs, errors.New("sink.types: не задан ни один приемник"))
+	}
+	for _, t := range c.Sink.Types {
+		switch t {
+		case "postgres", "nop":
+		case "file":
+			if c.Sink.ExportDir == "" {
+				errs = append(errs, errors.New("sink.export_dir: не задан каталог выгрузки"))
+			}
+		default:
+			errs = append(errs, fmt.Errorf("sink.types: ожидается postgres, file или nop, получено %q", t))
+		}
+	}
+	if c.Events.Enabled {
+		switch c.Events.Broker {
+		case "nats", "kafka", "file":
+		default:
+			errs = append(errs, fmt.Errorf("events.broker: ожидается nats, kafka или file, получено %q", c.Events.Broker))
+		}
+		if c.Events.URL == "" {
+			errs = append(errs, errors.New("events.url: не задан адрес брокера"))
+		}
+		if c.Events.Topic == "" && c.Events.Broker != "file" {
+			errs = append(errs, errors.New("events.topic: не задан топик"))
+		}
+		if !c.Diff || !slices.Contains(c.Sink.Types, "postgres") {
+			errs = append(errs, errors.New("events.enabled: события строятся по журналу изменений, нужны diff.enabled и приемник postgres"))
+		}
+	}
+	for _, name := range c.Derived {
+		if !slices.ContainsFunc(loader.DerivedTables, func(t loader.DerivedTable) bool { return t.Name == name }) {
+			errs = append(errs, fmt.Errorf("derived.tables: неизвестная таблица %q", name))
+		}
+	}
+	for name, t := range c.FormatTemplates {
+		if err := t.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("format.templates.%s.%w", name, err))
+		}
+	}
+	if c.KeepVersions < 0 {
+		errs = append(errs, fmt.Errorf("snapshots.keep: неверное значение %d", c.KeepVersions))
+	}
+	if c.Validation.Fail && c.KeepVersions == 0 {
+		errs = append(errs, fmt.Errorf("validate.fail: для отмены загрузки нужны снимки, задайте snapshots.keep > 0"))
+	}
+	if c.Validation.Sample < 0 {
+		errs = append(errs, fmt.Errorf("validate.sample: неверное значение %d", c.Validation.Sample))
+	}
+	if c.Sanity.MaxDropPercent < 0 || c.Sanity.MaxDropPercent > 100 {
+		errs = append(errs, fmt.Errorf("sanity.max_drop_percent: ожидается 0..100, получено %v", c.Sanity.MaxDropPercent))
+	}
+	for table, percent := range c.Sanity.Tables {
+		if percent < 0 || percent > 100 {
+			errs = append(errs, fmt.Errorf("sanity.tables.%s: ожидается 0..100, получено %v", table, percent))
+		}
+	}
+	switch strings.ToLower(c.LogFormat) {
+	case "text", "json":
+	default:
+		errs = append(errs, fmt.Errorf("log.format: ожидается text или json, получено %q", c.LogFormat))
+	}
+	if c.ProgressInterval <= 0 {
+		errs = append(errs, fmt.Errorf("log.progress_interval: неверный интервал %v", c.ProgressInterval))
+	}
+
+	return errors.Join(errs...)
+}
+
+// ConnectionString - строка подключения к БД (database.dsn имеет приоритет)
+func (c *Config) ConnectionString() string {
+	if c.Database.DSN != "" {
+		return c.Database.DSN
+	}
+
+	return fmt.Sprintf("host=%s port=%v user=%s password=%s dbname=%s sslmode=%s application_name='FIAS Parser'",
+		c.Database.Server, c.Database.Port, c.Database.User, quoteDSNValue(c.Database.Password), c.Database.Base, c.Database.SSLMode)
+}
+
+// quoteDSNValue - экранируем значение для строки подключения вида key=value
+func quoteDSNValue(v string) string {
+	if v != "" && !strings.ContainsAny(v, ` '\`) {
+		return v
+	}
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(v) + "'"
+}
+
+// canCheckNewFile - q: проверка на наличие новых файлов
+func (c *Config) canCheckNewFile() bool { return c.WorkRegime[0] == '1' }
+
+// canDownloadFile - w: загрузка файла
+func (c *Config) canDownloadFile() bool { return c.WorkRegime[1] == '1' }
+
+// canUnrarFile - e: распаковка файла
+func (c *Config) canUnrarFile() bool { return c.WorkRegime[2] == '1' }
+
+// canParseFile - r: парсинг файлов
+func (c *Config) canParseFile() bool { return c.WorkRegime[3] == '1' }
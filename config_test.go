@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/loader"
+	"github.com/SliderVM/FIASParse/fias/source"
+)
+
+// validConfig - настройки, которые проходят Validate
+func validConfig() *Config {
+	return &Config{
+		Database: DatabaseConfig{Server: "db", Port: 5432, User: "fias", Base: "fias", SSLMode: "disable"},
+		Service: source.Config{
+			URL:     source.DefaultServiceURL,
+			Timeout: time.Minute,
+		},
+		Validation:        ValidationConfig{Enabled: true, Sample: 10},
+		Sanity:            SanityConfig{MaxDropPercent: 20, Tables: map[string]float64{}},
+		Sink:              SinkConfig{Types: []string{"postgres"}, ExportDir: "export"},
+		Events:            EventsConfig{Broker: "nats", URL: "nats://127.0.0.1:4222", Topic: "fias"},
+		DirName:           "/FIAS/",
+		FileName:          "fias.rar",
+		WorkRegime:        "1111",
+		OnDecodeError:     loader.PolicyAbort,
+		QuarantineStorage: loader.QuarantineFile,
+		QuarantineDir:     "quarantine",
+		LogLevel:          "info",
+		LogFormat:         "text",
+		ProgressInterval:  30 * time.Second,
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		errs   []string // подстроки ожидаемых ошибок, пусто - ошибок нет
+	}{
+		{"по умолчанию", func(c *Config) {}, nil},
+		{"dsn вместо полей", func(c *Config) { c.Database = DatabaseConfig{DSN: "postgres://fias@db/fias"} }, nil},
+		{"work_regime", func(c *Config) { c.WorkRegime = "12" }, []string{"config.work_regime"}},
+		{"без БД", func(c *Config) { c.Database = DatabaseConfig{SSLMode: "off"} }, []string{
+			"database.server", "database.base", "database.user", "database.port", "database.sslmode",
+		}},
+		{"url сервиса", func(c *Config) { c.Service.URL = "fias.nalog.ru" }, []string{"service.url"}},
+		{"таймаут", func(c *Config) { c.Service.Timeout = 0 }, []string{"service.timeout"}},
+		{"выборка", func(c *Config) { c.Validation.Sample = -1 }, []string{"validate.sample"}},
+		{"on_error", func(c *Config) { c.OnDecodeError = "ignore" }, []string{"parse.on_error"}},
+		{"карантин", func(c *Config) { c.QuarantineStorage = "s3" }, []string{"parse.quarantine"}},
+		{"приемники", func(c *Config) { c.Sink.Types = []string{"postgres", "events"} }, []string{"sink.types"}},
+		{"без приемников", func(c *Config) { c.Sink.Types = nil }, []string{"sink.types"}},
+		{"file без каталога", func(c *Config) { c.Sink = SinkConfig{Types: []string{"file"}} }, []string{"sink.export_dir"}},
+		{"события без журнала", func(c *Config) { c.Events.Enabled = true }, []string{"events.enabled"}},
+		{"события", func(c *Config) { c.Events.Enabled = true; c.Diff = true }, nil},
+		{"брокер", func(c *Config) { c.Events = EventsConfig{Enabled: true, Broker: "redis"}; c.Diff = true }, []string{
+			"events.broker", "events.url", "events.topic",
+		}},
+		{"fail без снимков", func(c *Config) { c.Validation.Fail = true }, []string{"validate.fail"}},
+		{"fail со снимками", func(c *Config) { c.Validation.Fail = true; c.KeepVersions = 2 }, nil},
+		{"порог", func(c *Config) { c.Sanity.MaxDropPercent = 120; c.Sanity.Tables["house"] = -1 }, []string{
+			"sanity.max_drop_percent", "sanity.tables.house",
+		}},
+		{"derived", func(c *Config) { c.Derived = []string{"nope"} }, []string{"derived.tables"}},
+		{"log", func(c *Config) { c.LogFormat = "xml"; c.ProgressInterval = 0 }, []string{"log.format", "log.progress_interval"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validConfig()
+			tt.change(c)
+			err := c.Validate()
+			if len(tt.errs) == 0 {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("нет ошибки, ожидается %v", tt.errs)
+			}
+			for _, want := range tt.errs {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("в ошибке нет %q: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+// TestLoadConfig - поставляемый config.toml проходит проверку, переменные FIAS_* его переопределяют
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("FIAS_CONFIG_WORK_REGIME", "0001")
+	t.Setenv("FIAS_DATABASE_PASSWORD", "from-env")
+
+	cfg, err := loadConfig("config.toml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.WorkRegime != "0001" || cfg.Database.Password != "from-env" {
+		t.Errorf("work_regime = %q, password = %q: переменные окружения не применились", cfg.WorkRegime, cfg.Database.Password)
+	}
+}
@@ -0,0 +1,295 @@
+// Package api - HTTP API запросов к адресному реестру
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+	"github.com/SliderVM/FIASParse/fias/query"
+)
+
+// Server - HTTP обработчики поверх query.Store
+type Server struct {
+	Store  *query.Store
+	Logger *slog.Logger
+
+	mux *http.ServeMux
+}
+
+// New - сервер API для store
+func New(store *query.Store) *Server {
+	s := &Server{Store: store, Logger: slog.Default(), mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/objects/{guid}", s.objectAt)
+	s.mux.HandleFunc("GET /v1/objects/{guid}/timeline", s.timeline)
+	s.mux.HandleFunc("GET /v1/objects/{guid}/intervals", s.interval)
+	s.mux.HandleFunc("GET /v1/object-versions/{aoid}", s.objectVersionAt)
+	s.mux.HandleFunc("GET /v1/houses/{guid}", s.houseAt)
+	s.mux.HandleFunc("GET /v1/steads/{guid}", s.steadAt)
+	s.mux.HandleFunc("GET /v1/rooms/{guid}", s.roomAt)
+	s.mux.HandleFunc("GET /v1/search", s.search)
+	s.mux.HandleFunc("GET /v1/postal-codes/{code}", s.byPostalCode)
+	s.mux.HandleFunc("GET /v1/objects/{guid}/postal-code", s.postalCodeOf)
+	s.mux.HandleFunc("GET /v1/houses/{guid}/postal-code", s.postalCodeOf)
+	s.mux.HandleFunc("GET /v1/codes/{guid}", s.codesOf)
+	s.mux.HandleFunc("GET /v1/cadastral/{number}", s.byCadastral)
+	s.mux.HandleFunc("GET /v1/format/{guid}", s.format)
+	s.mux.HandleFunc("GET /v1/objects/{guid}/houses", s.findHouses)
+	s.mux.HandleFunc("GET /v1/house-label", s.parseHouseLabel)
+	s.mux.HandleFunc("GET /v1/houses/{guid}/rooms", s.findRooms)
+
+	return s
+}
+
+// ServeHTTP - обработка запроса
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// date - параметр date (ГГГГ-ММ-ДД), по умолчанию текущая дата
+func date(r *http.Request) (time.Time, error) {
+	value := r.URL.Query().Get("date")
+	if value == "" {
+		return time.Now(), nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
+// badRequest - ошибка в параметрах запроса
+type badRequest struct{ error }
+
+// handle - общий обработчик: разбор даты, вызов f и ответ JSON
+func (s *Server) handle(w http.ResponseWriter, r *http.Request, f func(at time.Time) (interface{}, error)) {
+	at, err := date(r)
+	if err != nil {
+		s.writeError(w, r, badRequest{errors.New("date: ожидается дата ГГГГ-ММ-ДД")})
+		return
+	}
+
+	result, err := f(at)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, r *http.Request, err error) {
+	status := http.StatusInternalServerError
+	var bad badRequest
+	switch {
+	case errors.As(err, &bad), errors.Is(err, query.ErrInvalidCadastral), errors.Is(err, query.ErrInvalidRoom),
+		errors.Is(err, query.ErrInvalidPostalCode):
+		status = http.StatusBadRequest
+	case errors.Is(err, query.ErrNotFound):
+		status = http.StatusNotFound
+	default:
+		s.Logger.Error("Ошибка запроса API", "path", r.URL.Path, "err", err)
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *Server) objectAt(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		return s.Store.AddressAt(r.Context(), r.PathValue("guid"), at)
+	})
+}
+
+func (s *Server) timeline(w http.ResponseWriter, r *http.Request) {
+	timeline, err := s.Store.Timeline(r.Context(), r.PathValue("guid"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, timeline)
+}
+
+func (s *Server) interval(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		house := r.URL.Query().Get("house")
+		if house == "" {
+			return nil, badRequest{errors.New("house: не задан номер дома")}
+		}
+		return s.Store.IntervalFor(r.Context(), r.PathValue("guid"), house, at)
+	})
+}
+
+func (s *Server) objectVersionAt(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		return s.Store.ObjectVersionAt(r.Context(), r.PathValue("aoid"), at)
+	})
+}
+
+func (s *Server) houseAt(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		return s.Store.HouseAt(r.Context(), r.PathValue("guid"), at)
+	})
+}
+
+func (s *Server) steadAt(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		return s.Store.SteadAt(r.Context(), r.PathValue("guid"), at)
+	})
+}
+
+func (s *Server) roomAt(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		return s.Store.RoomAt(r.Context(), r.PathValue("guid"), at)
+	})
+}
+
+func (s *Server) search(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			return nil, badRequest{errors.New("q: не задан адрес")}
+		}
+		return s.Store.FindAt(r.Context(), q, at)
+	})
+}
+
+func (s *Server) byPostalCode(w http.ResponseWriter, r *http.Request) {
+	code, err := query.ParsePostalCode(r.PathValue("code"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+
+	area, err := s.Store.ByPostalCode(r.Context(), code)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, area)
+}
+
+func (s *Server) postalCodeOf(w http.ResponseWriter, r *http.Request) {
+	code, err := s.Store.PostalCodeOf(r.Context(), r.PathValue("guid"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, code)
+}
+
+func (s *Server) codesOf(w http.ResponseWriter, r *http.Request) {
+	codes, err := s.Store.CodesOf(r.Context(), r.PathValue("guid"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, codes)
+}
+
+func (s *Server) byCadastral(w http.ResponseWriter, r *http.Request) {
+	matches, err := s.Store.ByCadastral(r.Context(), r.PathValue("number"))
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, matches)
+}
+
+// template - шаблон из параметра template (по умолчанию oneline), параметры order, names,
+// postal, region и separator переопределяют его поля
+func template(r *http.Request) (query.Template, error) {
+	params := r.URL.Query()
+	name := params.Get("template")
+	if name == "" {
+		name = "oneline"
+	}
+	t, ok := query.Templates[name]
+	if !ok {
+		return t, fmt.Errorf("template: неизвестный шаблон %q", name)
+	}
+
+	if v := params.Get("order"); v != "" {
+		t.Order = v
+	}
+	if v := params.Get("names"); v != "" {
+		t.Names = v
+	}
+	if v := params.Get("separator"); v != "" {
+		t.Separator = v
+	}
+	for key, dst := range map[string]*bool{"postal": &t.PostalCode, "region": &t.Region} {
+		if v := params.Get(key); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return t, fmt.Errorf("%s: ожидается true или false", key)
+			}
+			*dst = b
+		}
+	}
+
+	return t, t.Validate()
+}
+
+func (s *Server) format(w http.ResponseWriter, r *http.Request) {
+	t, err := template(r)
+	if err != nil {
+		s.writeError(w, r, badRequest{err})
+		return
+	}
+
+	text, err := s.Store.Format(r.Context(), r.PathValue("guid"), t)
+	if err != nil {
+		s.writeError(w, r, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"guid": r.PathValue("guid"), "address": text})
+}
+
+func (s *Server) findHouses(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		label, err := model.ParseHouseLabel(r.URL.Query().Get("label"))
+		if err != nil {
+			return nil, badRequest{err}
+		}
+		houses, err := s.Store.FindHouses(r.Context(), r.PathValue("guid"), label, at)
+		if err == nil && len(houses) == 0 {
+			err = query.ErrNotFound
+		}
+		return houses, err
+	})
+}
+
+// parseHouseLabel - разбор строки номера дома и канонический вид
+func (s *Server) parseHouseLabel(w http.ResponseWriter, r *http.Request) {
+	label, err := model.ParseHouseLabel(r.URL.Query().Get("q"))
+	if err != nil {
+		s.writeError(w, r, badRequest{err})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"label":     label.String(),
+		"full":      label.Full(),
+		"eststatus": label.EstStatus,
+		"housenum":  label.HouseNum,
+		"buildnum":  label.BuildNum,
+		"strstatus": label.StrStatus,
+		"strucnum":  label.StrucNum,
+	})
+}
+
+func (s *Server) findRooms(w http.ResponseWriter, r *http.Request) {
+	s.handle(w, r, func(at time.Time) (interface{}, error) {
+		q := r.URL.Query().Get("q")
+		if q == "" {
+			return nil, badRequest{errors.New("q: не задан номер помещения")}
+		}
+		return s.Store.FindRooms(r.Context(), r.PathValue("guid"), q, at)
+	})
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/query"
+)
+
+// TestRoutes - маршруты с шаблонами {name} доступны: неверные параметры отклоняются
+// обработчиком с 400 до обращения к БД, а не мультиплексором с 404
+func TestRoutes(t *testing.T) {
+	srv := httptest.NewServer(New(query.New(nil)))
+	defer srv.Close()
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/v1/objects/0c5b2444-70a0-4932-980c-b4dc0d3f02b5?date=вчера", http.StatusBadRequest},
+		{"/v1/objects/0c5b2444-70a0-4932-980c-b4dc0d3f02b5/intervals", http.StatusBadRequest},
+		{"/v1/object-versions/5c8b06f1-518e-496e-b683-7bf917e0d70b?date=2020-13-01", http.StatusBadRequest},
+		{"/v1/objects/0c5b2444-70a0-4932-980c-b4dc0d3f02b5/houses?label=", http.StatusBadRequest},
+		{"/v1/houses/0c5b2444-70a0-4932-980c-b4dc0d3f02b5/rooms", http.StatusBadRequest},
+		{"/v1/search", http.StatusBadRequest},
+		{"/v1/postal-codes/1234", http.StatusBadRequest},
+		{"/v1/postal-codes/+12345", http.StatusBadRequest},
+		{"/v1/postal-codes/-12345", http.StatusBadRequest},
+		{"/v1/postal-codes/12345a", http.StatusBadRequest},
+		{"/v1/cadastral/не-номер", http.StatusBadRequest},
+		{"/v1/format/0c5b2444-70a0-4932-980c-b4dc0d3f02b5?template=нет", http.StatusBadRequest},
+		{"/v1/house-label?q=", http.StatusBadRequest},
+		{"/v1/house-label?q=д+12+к+1", http.StatusOK},
+		{"/v1/unknown", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + tt.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != tt.status {
+				t.Errorf("статус %d, ожидается %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	postal := query.Templates["postal"]
+	custom := query.Templates["oneline"]
+	custom.Order, custom.Names, custom.Separator = query.OrderDesc, query.NamesNone, " / "
+	custom.PostalCode, custom.Region = true, false
+
+	tests := []struct {
+		query string
+		want  query.Template
+	}{
+		{"", query.Templates["oneline"]},
+		{"template=postal", postal},
+		{"order=desc&names=none&separator=+%2F+&postal=true&region=0", custom},
+	}
+	for _, tt := range tests {
+		got, err := template(httptest.NewRequest(http.MethodGet, "/v1/format/g?"+tt.query, nil))
+		if err != nil {
+			t.Errorf("%q: %v", tt.query, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: шаблон %+v, ожидается %+v", tt.query, got, tt.want)
+		}
+	}
+
+	for _, q := range []string{"template=нет", "order=up", "names=long", "postal=да", "region=2"} {
+		if got, err := template(httptest.NewRequest(http.MethodGet, "/v1/format/g?"+q, nil)); err == nil {
+			t.Errorf("%q: шаблон %+v без ошибки", q, got)
+		}
+	}
+}
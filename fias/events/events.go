@@ -0,0 +1,150 @@
+// Package events - события об изменениях адресного реестра для брокера сообщений
+package events
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/loader"
+	"github.com/SliderVM/FIASParse/fias/model"
+	"github.com/lib/pq"
+)
+
+// Action - вид изменения записи
+type Action string
+
+// Виды изменений
+const (
+	Created Action = "created"
+	Updated Action = "updated"
+	Deleted Action = "deleted"
+)
+
+// Event - изменение одной записи в версии ФИАС
+type Event struct {
+	Action     Action                 `json:"action"`
+	Entity     string                 `json:"entity"` // XML элемент: Object, House, Room...
+	GUID       string                 `json:"guid"`
+	Version    int                    `json:"version"`
+	Table      string                 `json:"table"`
+	OperStatus int                    `json:"oper_status,omitempty"`
+	Time       time.Time              `json:"time"`
+	Record     map[string]interface{} `json:"record,omitempty"`
+}
+
+// Publisher - отправка событий в брокер, Publish возвращается, когда брокер принял события
+type Publisher interface {
+	Publish(ctx context.Context, events []Event) error
+	Close() error
+}
+
+// ActionFor - вид события по изменениям записей одного GUID в журнале <таблица>_changes.
+// Журнал ведется по id версий записи, поэтому created и deleted - только если GUID
+// не было в прежней таблице или не осталось в новой (kept), иначе это изменение объекта
+func ActionFor(changes []string, kept bool) Action {
+	if !kept && len(changes) == 1 {
+		switch changes[0] {
+		case loader.ChangeAdded:
+			return Created
+		case loader.ChangeRemoved:
+			return Deleted
+		}
+	}
+	return Updated
+}
+
+// Entities - XML элемент записей таблиц из loader.DiffKeys
+var Entities = map[string]string{
+	"address_objects": model.Object{}.Element(),
+	"house":           model.House{}.Element(),
+	"rooms":           model.Room{}.Element(),
+	"steads":          model.Stead{}.Element(),
+}
+
+// batchSize - сколько записей журнала отправлять за раз
+const batchSize = 1000
+
+// PublishChanges - отправляем события по журналу изменений версии version; вызывается
+// после замены таблиц, поэтому отклоненная проверкой версия событий не порождает.
+// includeRecord - добавлять в событие все значения записи
+func PublishChanges(ctx context.Context, db *sql.DB, publisher Publisher, version int, includeRecord bool, logger *slog.Logger) (total int64, err error) {
+	for _, table := range slices.Sorted(maps.Keys(Entities)) {
+		count, err := publishTable(ctx, db, publisher, table, version, includeRecord)
+		if err != nil {
+			return total, fmt.Errorf("события %s: %w", table, err)
+		}
+		if count > 0 {
+			logger.Info("События отправлены", "table", table, "events", count)
+		}
+		total += count
+	}
+
+	return total, nil
+}
+
+// publishTable - события по журналу таблицы table, одно на GUID, пачками по batchSize.
+// В событие попадает последняя добавленная или измененная запись GUID, для удаленного
+// объекта - последняя удаленная
+func publishTable(ctx context.Context, db *sql.DB, publisher Publisher, table string, version int, includeRecord bool) (int64, error) {
+	changes := loader.ChangesTable(table)
+
+	var exists bool
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass($1) IS NOT NULL;", changes).Scan(&exists); err != nil || !exists {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT guid, changes, kept, coalesce((record->>'operstatus')::integer, 0), record, created_at FROM (
+	SELECT guid, array_agg(DISTINCT change) AS changes, bool_or(guid_kept) AS kept,
+		(array_agg(record ORDER BY change = 'removed', id DESC))[1] AS record, max(created_at) AS created_at
+	FROM %s WHERE version_id = $1 AND guid > $2
+	GROUP BY guid ORDER BY guid LIMIT %d
+) c ORDER BY guid;`, changes, batchSize)
+
+	var total int64
+	last := ""
+	for {
+		rows, err := db.QueryContext(ctx, query, version, last)
+		if err != nil {
+			return total, err
+		}
+
+		var events []Event
+		for rows.Next() {
+			var kinds []string
+			var kept bool
+			var record []byte
+			e := Event{Entity: Entities[table], Version: version, Table: table}
+			if err := rows.Scan(&e.GUID, pq.Array(&kinds), &kept, &e.OperStatus, &record, &e.Time); err != nil {
+				rows.Close()
+				return total, err
+			}
+			e.Action = ActionFor(kinds, kept)
+			if includeRecord {
+				if err := json.Unmarshal(record, &e.Record); err != nil {
+					rows.Close()
+					return total, err
+				}
+			}
+			events = append(events, e)
+			last = e.GUID
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return total, err
+		}
+
+		if len(events) == 0 {
+			return total, nil
+		}
+		if err := publisher.Publish(ctx, events); err != nil {
+			return total, err
+		}
+		total += int64(len(events))
+	}
+}
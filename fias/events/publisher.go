@@ -0,0 +1,118 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+	"github.com/segmentio/kafka-go"
+)
+
+// NATSPublisher - события в NATS, тема <Subject>.<entity>.<action>
+type NATSPublisher struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATSPublisher - подключение к NATS по адресу url
+func NewNATSPublisher(url, subject string) (*NATSPublisher, error) {
+	conn, err := nats.Connect(url, nats.Name("FIASParse"))
+	if err != nil {
+		return nil, fmt.Errorf("подключение к NATS %s: %w", url, err)
+	}
+	return &NATSPublisher{conn: conn, subject: subject}, nil
+}
+
+// Publish - отправляем события и ждем, пока сервер их получит (Flush). Core NATS
+// не хранит сообщения: подписчики, не подключенные в момент отправки, их не получат
+func (p *NATSPublisher) Publish(ctx context.Context, events []Event) error {
+	for _, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		subject := p.subject + "." + strings.ToLower(e.Entity) + "." + string(e.Action)
+		if err := p.conn.Publish(subject, data); err != nil {
+			return fmt.Errorf("отправка в NATS %s: %w", subject, err)
+		}
+	}
+	return p.conn.Flush()
+}
+
+// Close - закрываем подключение
+func (p *NATSPublisher) Close() error {
+	p.conn.Close()
+	return nil
+}
+
+// KafkaPublisher - события в топик Kafka, ключ сообщения - GUID записи
+type KafkaPublisher struct {
+	writer *kafka.Writer
+}
+
+// NewKafkaPublisher - отправка в топик topic через брокеры brokers
+func NewKafkaPublisher(brokers []string, topic string) *KafkaPublisher {
+	return &KafkaPublisher{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+	}}
+}
+
+// Publish - отправляем события одной пачкой
+func (p *KafkaPublisher) Publish(ctx context.Context, events []Event) error {
+	messages := make([]kafka.Message, len(events))
+	for i, e := range events {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		messages[i] = kafka.Message{Key: []byte(e.GUID), Value: data}
+	}
+	if err := p.writer.WriteMessages(ctx, messages...); err != nil {
+		return fmt.Errorf("отправка в Kafka %s: %w", p.writer.Topic, err)
+	}
+	return nil
+}
+
+// Close - закрываем writer
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
+
+// WriterPublisher - события в JSON Lines (stdout, файл) для отладки без брокера
+type WriterPublisher struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	w   io.Writer
+}
+
+// NewWriterPublisher - события в w
+func NewWriterPublisher(w io.Writer) *WriterPublisher {
+	return &WriterPublisher{enc: json.NewEncoder(w), w: w}
+}
+
+// Publish - пишем события построчно
+func (p *WriterPublisher) Publish(ctx context.Context, events []Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range events {
+		if err := p.enc.Encode(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close - закрываем w, если это файл
+func (p *WriterPublisher) Close() error {
+	if c, ok := p.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
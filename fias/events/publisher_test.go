@@ -0,0 +1,179 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SliderVM/FIASParse/fias/loader"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// runNATS - встроенный сервер NATS на свободном порту
+func runNATS(t *testing.T) *server.Server {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("сервер NATS не запустился")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func TestNATSPublisher(t *testing.T) {
+	srv := runNATS(t)
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	messages := make(chan *nats.Msg, 10)
+	if _, err := conn.ChanSubscribe("fias.>", messages); err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	publisher, err := NewNATSPublisher(srv.ClientURL(), "fias")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer publisher.Close()
+
+	sent := []Event{
+		{Action: Created, Entity: "House", GUID: "9c2d1f5e-0b0e-4c0d-9a57-1b3e6c3a2f10", Version: 634, Table: "house"},
+		{Action: Deleted, Entity: "Object", GUID: "0c5b2444-70a0-4932-980c-b4dc0d3f02b5", Version: 634, Table: "address_objects", OperStatus: 30},
+	}
+	if err := publisher.Publish(context.Background(), sent); err != nil {
+		t.Fatal(err)
+	}
+
+	subjects := []string{"fias.house.created", "fias.object.deleted"}
+	for i, want := range sent {
+		select {
+		case msg := <-messages:
+			if msg.Subject != subjects[i] {
+				t.Errorf("тема %q, ожидается %q", msg.Subject, subjects[i])
+			}
+			var got Event
+			if err := json.Unmarshal(msg.Data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.GUID != want.GUID || got.Action != want.Action || got.Version != want.Version || got.OperStatus != want.OperStatus {
+				t.Errorf("событие %+v, ожидается %+v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("не получено событие %s", subjects[i])
+		}
+	}
+}
+
+func TestWriterPublisher(t *testing.T) {
+	var buf bytes.Buffer
+	publisher := NewWriterPublisher(&buf)
+	events := []Event{{Action: Updated, Entity: "Room", GUID: "a"}, {Action: Created, Entity: "Stead", GUID: "b"}}
+	if err := publisher.Publish(context.Background(), events); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != len(events) {
+		t.Fatalf("строк %d, ожидается %d", len(lines), len(events))
+	}
+	for i, line := range lines {
+		var got Event
+		if err := json.Unmarshal(line, &got); err != nil {
+			t.Fatal(err)
+		}
+		if got.GUID != events[i].GUID || got.Action != events[i].Action {
+			t.Errorf("строка %d: %+v, ожидается %+v", i, got, events[i])
+		}
+	}
+}
+
+func TestActionFor(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []string
+		kept    bool
+		want    Action
+	}{
+		{"новый объект", []string{loader.ChangeAdded}, false, Created},
+		{"новая версия объекта", []string{loader.ChangeAdded}, true, Updated},
+		{"новая версия и прежняя изменена", []string{loader.ChangeAdded, loader.ChangeChanged}, true, Updated},
+		{"изменена запись", []string{loader.ChangeChanged}, true, Updated},
+		{"объект удален", []string{loader.ChangeRemoved}, false, Deleted},
+		{"удалена версия, объект остался", []string{loader.ChangeRemoved}, true, Updated},
+		{"версия заменена", []string{loader.ChangeAdded, loader.ChangeRemoved}, true, Updated},
+	}
+	for _, tt := range tests {
+		if got := ActionFor(tt.changes, tt.kept); got != tt.want {
+			t.Errorf("%s: ActionFor(%v, %v) = %q, ожидается %q", tt.name, tt.changes, tt.kept, got, tt.want)
+		}
+	}
+}
+
+func TestPublishChanges(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, table := range []string{"address_objects", "house", "rooms", "steads"} {
+		exists := sqlmock.NewRows([]string{"exists"}).AddRow(table == "house")
+		mock.ExpectQuery(`SELECT to_regclass`).WithArgs(table + "_changes").WillReturnRows(exists)
+		if table != "house" {
+			continue
+		}
+		columns := []string{"guid", "changes", "kept", "operstatus", "record", "created_at"}
+		now := time.Now()
+		mock.ExpectQuery(`FROM house_changes WHERE version_id = \$1 AND guid > \$2\s+GROUP BY guid`).
+			WithArgs(634, "").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("a", "{added}", false, 10, `{"houseguid": "a"}`, now).
+				AddRow("b", "{added,changed}", true, 20, `{"houseguid": "b"}`, now).
+				AddRow("c", "{removed}", false, 0, `{"houseguid": "c"}`, now))
+		mock.ExpectQuery(`FROM house_changes`).WithArgs(634, "c").WillReturnRows(sqlmock.NewRows(columns))
+	}
+
+	var buf bytes.Buffer
+	total, err := PublishChanges(context.Background(), db, NewWriterPublisher(&buf), 634, false, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+	if total != 3 {
+		t.Fatalf("отправлено %d событий, ожидается 3", total)
+	}
+
+	want := []Event{
+		{Action: Created, Entity: "House", GUID: "a", OperStatus: 10},
+		{Action: Updated, Entity: "House", GUID: "b", OperStatus: 20},
+		{Action: Deleted, Entity: "House", GUID: "c"},
+	}
+	dec := json.NewDecoder(&buf)
+	for _, w := range want {
+		var got Event
+		if err := dec.Decode(&got); err != nil {
+			t.Fatal(err)
+		}
+		if got.Action != w.Action || got.Entity != w.Entity || got.GUID != w.GUID || got.OperStatus != w.OperStatus || got.Version != 634 || got.Record != nil {
+			t.Errorf("событие %+v, ожидается %+v", got, w)
+		}
+	}
+}
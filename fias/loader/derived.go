@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DerivedTable - производная таблица, пересчитываемая после загрузки версии
+type DerivedTable struct {
+	Name    string
+	Refresh func(db *sql.DB) (rows int64, err error)
+}
+
+// DerivedTables - известные производные таблицы
+var DerivedTables = []DerivedTable{
+	{"house_interval_numbers", refreshHouseIntervalNumbers},
+	{"postal_codes", refreshPostalCodes},
+}
+
+// maxIntervalSize - интервалы шире этого не разворачиваются (ошибки в данных вида 1-99999)
+const maxIntervalSize = 10000
+
+// RefreshDerived - пересчитываем производные таблицы names
+func RefreshDerived(db *sql.DB, names []string, logger *slog.Logger) error {
+	for _, name := range names {
+		var table *DerivedTable
+		for i := range DerivedTables {
+			if DerivedTables[i].Name == name {
+				table = &DerivedTables[i]
+			}
+		}
+		if table == nil {
+			return fmt.Errorf("неизвестная производная таблица %s", name)
+		}
+
+		started := time.Now()
+		rows, err := table.Refresh(db)
+		if err != nil {
+			logger.Error("Ошибка пересчета производной таблицы", "table", name, "err", err)
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		logger.Info("Производная таблица пересчитана", "table", name, "rows", rows, "duration", time.Since(started))
+	}
+	return nil
+}
+
+// replaceTable - строим таблицу name запросом create во временной таблице и подменяем текущую
+func replaceTable(db *sql.DB, name, create string, indexes ...string) (int64, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	temp := name + "_new"
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + temp + ";"); err != nil {
+		return 0, err
+	}
+	result, err := tx.Exec("CREATE TABLE " + temp + " AS " + create)
+	if err != nil {
+		return 0, err
+	}
+	rows, _ := result.RowsAffected()
+
+	for _, index := range indexes {
+		if _, err := tx.Exec(fmt.Sprintf(index, temp)); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec("DROP TABLE IF EXISTS " + name + "; ALTER TABLE " + temp + " RENAME TO " + name + ";"); err != nil {
+		return 0, err
+	}
+
+	return rows, tx.Commit()
+}
+
+// refreshHouseIntervalNumbers - действующие интервалы домов, развернутые в отдельные номера
+func refreshHouseIntervalNumbers(db *sql.DB) (int64, error) {
+	return replaceTable(db, "house_interval_numbers", fmt.Sprintf(`SELECT i.aoguid, n AS housenum, i.intguid,
+	i.postalcode, i.okato, i.oktmo, i.ifnsfl, i.ifnsul
+FROM house_interval i CROSS JOIN LATERAL generate_series(i.intstart, i.intend) n
+WHERE i.startdate <= current_date AND (i.enddate IS NULL OR i.enddate > current_date)
+	AND i.intend - i.intstart <= %d
+	AND (i.intstatus NOT IN (2, 3) OR (i.intstatus = 2 AND n %% 2 = 0) OR (i.intstatus = 3 AND n %% 2 = 1));`, maxIntervalSize),
+		"CREATE INDEX ON %s (aoguid, housenum);")
+}
+
+// refreshPostalCodes - индекс почтовых кодов действующих объектов, домов и участков;
+// пустой индекс наследуется от ближайшего родителя (inherited = true)
+func refreshPostalCodes(db *sql.DB) (int64, error) {
+	return replaceTable(db, "postal_codes", `WITH RECURSIVE obj AS (
+	SELECT aoguid, postalcode AS effective, false AS inherited FROM address_objects
+	WHERE actstatus = 1 AND coalesce(parentguid, '') = ''
+	UNION ALL
+	SELECT c.aoguid, coalesce(c.postalcode, p.effective), c.postalcode IS NULL AND p.effective IS NOT NULL
+	FROM address_objects c JOIN obj p ON c.parentguid = p.aoguid
+	WHERE c.actstatus = 1
+)
+SELECT effective AS postalcode, 'object'::text AS kind, aoguid AS guid, aoguid, inherited FROM obj
+WHERE effective IS NOT NULL
+UNION ALL
+SELECT coalesce(h.postalcode, o.effective), 'house', h.houseguid, h.aoguid, h.postalcode IS NULL
+FROM house h JOIN obj o ON o.aoguid = h.aoguid
+WHERE h.startdate <= current_date AND (h.enddate IS NULL OR h.enddate > current_date)
+	AND coalesce(h.postalcode, o.effective) IS NOT NULL
+UNION ALL
+SELECT coalesce(s.postalcode, o.effective), 'stead', s.steadguid, s.parentguid, s.postalcode IS NULL
+FROM steads s JOIN obj o ON o.aoguid = s.parentguid
+WHERE s.startdate <= current_date AND (s.enddate IS NULL OR s.enddate > current_date)
+	AND coalesce(s.postalcode, o.effective) IS NOT NULL;`,
+		"CREATE INDEX ON %s (postalcode, kind);", "CREATE INDEX ON %s (guid);")
+}
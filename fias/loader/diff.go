@@ -0,0 +1,113 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// DiffKeys - ключ записи версии для сравнения таблиц
+var DiffKeys = map[string]string{
+	"address_objects": "aoid",
+	"house":           "houseid",
+	"rooms":           "roomid",
+	"steads":          "steadid",
+}
+
+// DiffGUIDs - GUID объекта учета, общий для всех версий его записи
+var DiffGUIDs = map[string]string{
+	"address_objects": "aoguid",
+	"house":           "houseguid",
+	"rooms":           "roomguid",
+	"steads":          "steadguid",
+}
+
+// Виды изменений в журнале <таблица>_changes
+const (
+	ChangeAdded   = "added"
+	ChangeChanged = "changed"
+	ChangeRemoved = "removed"
+)
+
+// DiffStats - кол-во изменений между версиями таблицы
+type DiffStats struct {
+	Added   int64
+	Changed int64
+	Removed int64
+}
+
+// ChangesTable - таблица журнала изменений для table
+func ChangesTable(table string) string {
+	return table + "_changes"
+}
+
+// DiffTables - сравниваем новую версию staging с текущей таблицей table по ключу из DiffKeys
+// и пишем добавленные, измененные и удаленные записи в журнал <table>_changes с версией version.
+// Ключ - id версии записи, поэтому в guid_kept отмечаем, что GUID добавленной записи
+// был в прежней таблице, а GUID удаленной остался в новой: это изменение объекта, а не
+// его создание или удаление
+func DiffTables(db *sql.DB, table, staging string, version int) (stats DiffStats, err error) {
+	// журнал версии 0 нельзя отличить от других загрузок без версии, а повторная
+	// запись стерла бы его
+	if version == 0 {
+		return stats, fmt.Errorf("журнал изменений %s: не задана версия загрузки", table)
+	}
+	key, ok := DiffKeys[table]
+	if !ok {
+		return stats, fmt.Errorf("для таблицы %s не задан ключ сравнения", table)
+	}
+	guid := DiffGUIDs[table]
+	changes := ChangesTable(table)
+
+	// журналы, созданные до guid_kept, дополняем колонками
+	_, err = db.Exec(fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %[1]s (
+	id bigserial PRIMARY KEY,
+	version_id integer NOT NULL,
+	change text NOT NULL,
+	%[2]s text NOT NULL,
+	record jsonb NOT NULL,
+	previous jsonb,
+	created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+ALTER TABLE %[1]s ADD COLUMN IF NOT EXISTS guid text, ADD COLUMN IF NOT EXISTS guid_kept boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS %[1]s_version_idx ON %[1]s (version_id);
+CREATE INDEX IF NOT EXISTS %[1]s_guid_idx ON %[1]s (version_id, guid);`, changes, key))
+	if err != nil {
+		return stats, fmt.Errorf("создание %s: %w", changes, err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Rollback()
+
+	// повторный запуск для той же версии перезаписывает журнал
+	if _, err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE version_id = $1;", changes), version); err != nil {
+		return stats, fmt.Errorf("очистка %s: %w", changes, err)
+	}
+
+	queries := []struct {
+		count *int64
+		query string
+	}{
+		{&stats.Added, `INSERT INTO %[1]s (version_id, change, %[2]s, guid, guid_kept, record)
+SELECT $1, 'added', n.%[2]s, n.%[5]s, EXISTS (SELECT 1 FROM %[4]s o WHERE o.%[5]s = n.%[5]s), to_jsonb(n) FROM %[3]s n
+WHERE NOT EXISTS (SELECT 1 FROM %[4]s o WHERE o.%[2]s = n.%[2]s);`},
+		{&stats.Changed, `INSERT INTO %[1]s (version_id, change, %[2]s, guid, guid_kept, record, previous)
+SELECT $1, 'changed', n.%[2]s, n.%[5]s, true, to_jsonb(n), to_jsonb(o) FROM %[3]s n
+JOIN %[4]s o ON o.%[2]s = n.%[2]s
+WHERE to_jsonb(n) IS DISTINCT FROM to_jsonb(o);`},
+		{&stats.Removed, `INSERT INTO %[1]s (version_id, change, %[2]s, guid, guid_kept, record)
+SELECT $1, 'removed', o.%[2]s, o.%[5]s, EXISTS (SELECT 1 FROM %[3]s n WHERE n.%[5]s = o.%[5]s), to_jsonb(o) FROM %[4]s o
+WHERE NOT EXISTS (SELECT 1 FROM %[3]s n WHERE n.%[2]s = o.%[2]s);`},
+	}
+	for _, q := range queries {
+		result, err := tx.Exec(fmt.Sprintf(q.query, changes, key, staging, table, guid), version)
+		if err != nil {
+			return stats, fmt.Errorf("сравнение %s и %s: %w", staging, table, err)
+		}
+		*q.count, _ = result.RowsAffected()
+	}
+
+	return stats, tx.Commit()
+}
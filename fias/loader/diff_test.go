@@ -0,0 +1,69 @@
+package loader
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestDiffTables(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS house_changes (")
+	mock.ExpectBegin()
+	expectExec(mock, "DELETE FROM house_changes WHERE version_id = $1;", 634)
+	// добавлены записи staging без houseid в house, guid_kept - GUID был в house
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO house_changes (version_id, change, houseid, guid, guid_kept, record)
+SELECT $1, 'added', n.houseid, n.houseguid, EXISTS (SELECT 1 FROM house o WHERE o.houseguid = n.houseguid), to_jsonb(n) FROM house_staging n
+WHERE NOT EXISTS (SELECT 1 FROM house o WHERE o.houseid = n.houseid);`)).
+		WithArgs(634).WillReturnResult(sqlmock.NewResult(0, 3))
+	// изменены записи с тем же houseid и другими данными
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT $1, 'changed', n.houseid, n.houseguid, true, to_jsonb(n), to_jsonb(o) FROM house_staging n
+JOIN house o ON o.houseid = n.houseid
+WHERE to_jsonb(n) IS DISTINCT FROM to_jsonb(o);`)).
+		WithArgs(634).WillReturnResult(sqlmock.NewResult(0, 2))
+	// удалены записи house без houseid в staging, guid_kept - GUID остался в staging
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT $1, 'removed', o.houseid, o.houseguid, EXISTS (SELECT 1 FROM house_staging n WHERE n.houseguid = o.houseguid), to_jsonb(o) FROM house o
+WHERE NOT EXISTS (SELECT 1 FROM house_staging n WHERE n.houseid = o.houseid);`)).
+		WithArgs(634).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	stats, err := DiffTables(db, "house", "house_staging", 634)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (DiffStats{Added: 3, Changed: 2, Removed: 1}); stats != want {
+		t.Errorf("изменения %+v, ожидается %+v", stats, want)
+	}
+
+	// ошибка сравнения отменяет журнал версии
+	failed := errors.New("нет таблицы")
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS house_changes (")
+	mock.ExpectBegin()
+	expectExec(mock, "DELETE FROM house_changes", 635)
+	mock.ExpectExec(`'added'`).WithArgs(635).WillReturnError(failed)
+	mock.ExpectRollback()
+
+	if _, err := DiffTables(db, "house", "house_staging", 635); !errors.Is(err, failed) {
+		t.Errorf("ошибка %v, ожидается %v", err, failed)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+
+	for _, tt := range []struct {
+		table   string
+		version int
+	}{{"house", 0}, {"actstat", 634}} {
+		if _, err := DiffTables(db, tt.table, tt.table+"_staging", tt.version); err == nil {
+			t.Errorf("DiffTables(%s, %d) без ошибки", tt.table, tt.version)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	"gopkg.in/doug-martin/goqu.v3"
+)
+
+// CurrentVersion - загруженная версия из настройки TextVersion таблицы config
+func CurrentVersion(db *sql.DB) (string, error) {
+	gq := goqu.New("postgres", db)
+	query, _, _ := gq.From("config").Select("value").Where(goqu.Ex{
+		"id": "TextVersion",
+	}).ToSql()
+
+	var fileVersion string
+	err := db.QueryRow(query).Scan(&fileVersion)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("не найдена настройка TextVersion")
+	}
+
+	return fileVersion, err
+}
+
+// RecordLoad - записываем VersionId сервиса в config (TextVersion) и в историю загрузок
+func RecordLoad(db *sql.DB, versionID int, source string) error {
+	gq := goqu.New("postgres", db)
+
+	update := gq.From("config").
+		Where(goqu.I("id").Eq("TextVersion")).
+		Update(goqu.Record{"value": versionID})
+	if _, err := update.Exec(); err != nil {
+		return fmt.Errorf("запись версии файла: %w", err)
+	}
+
+	return RecordHistory(db, versionID, source)
+}
+
+// RecordHistory - записываем только в историю загрузок, TextVersion не меняется
+func RecordHistory(db *sql.DB, versionID int, source string) error {
+	gq := goqu.New("postgres", db)
+
+	_, err := db.Exec(`CREATE TABLE IF NOT EXISTS load_history (
+	id serial PRIMARY KEY,
+	version_id integer NOT NULL,
+	source text NOT NULL,
+	loaded_at timestamp with time zone NOT NULL
+);`)
+	if err != nil {
+		return fmt.Errorf("создание load_history: %w", err)
+	}
+
+	insert := gq.From("load_history").Insert(goqu.Record{
+		"version_id": versionID,
+		"source":     source,
+		"loaded_at":  time.Now(),
+	})
+	if _, err := insert.Exec(); err != nil {
+		return fmt.Errorf("запись истории загрузок: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,307 @@
+// Package loader - загрузка XML файлов ФИАС в PostgreSQL
+package loader
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+	_ "gopkg.in/doug-martin/goqu.v3/adapters/postgres"
+)
+
+// batchSize - сколько записей вставлять одним запросом
+const batchSize = 5000
+
+// ProgressFunc - обертка reader для вывода прогресса, возвращает функцию завершения
+type ProgressFunc func(r io.Reader, size int64, logger *slog.Logger) (io.Reader, func())
+
+// Loader - загрузчик файлов ФИАС в БД
+type Loader struct {
+	DB *sql.DB
+
+	// OnError - что делать с записью, которую не удалось разобрать или вставить (Policy*)
+	OnError           string
+	QuarantineStorage string
+	QuarantineDir     string
+
+	// Version - версия ФИАС текущей загрузки
+	Version int
+
+	// Sink - куда пишутся записи, по умолчанию PostgreSQL
+	Sink Sink
+
+	// Schemas - файлы без описания в Registry загружать по XSD схемам из архива
+	Schemas bool
+	// FailOnUnknown - ошибка загрузки, если в каталоге есть нераспознанные файлы ФИАС
+	FailOnUnknown bool
+
+	Progress ProgressFunc
+	Logger   *slog.Logger
+}
+
+// ParseStats - итог загрузки файла
+type ParseStats struct {
+	Rows    int64
+	Skipped int64
+}
+
+// New - загрузчик в PostgreSQL с настройками по умолчанию
+func New(db *sql.DB) *Loader {
+	return &Loader{
+		DB:                db,
+		OnError:           PolicyAbort,
+		QuarantineStorage: QuarantineFile,
+		QuarantineDir:     "quarantine",
+		Sink:              NewPostgresSink(db),
+		Schemas:           true,
+		Logger:            slog.Default(),
+	}
+}
+
+// rowReader - источник записей файла для загрузки
+type rowReader interface {
+	NextRow() (Row, error)
+	InputOffset() int64
+}
+
+// Parse - загружаем файл f с записями T в таблицу table
+func Parse[T model.Record](l *Loader, f string, table string) (stats ParseStats, err error) {
+	var zero T
+	return l.parse(f, table, zero.Element(), func(r io.Reader) rowReader { return NewReader[T](r) })
+}
+
+// parse - загружаем файл f с элементами elementName в таблицу table, записи читает reader
+func (l *Loader) parse(f string, table string, elementName string, newReader func(io.Reader) rowReader) (stats ParseStats, err error) {
+	logger := l.Logger.With("table", table, "file", filepath.Base(f))
+	logger.Info("Открываем файл", "element", elementName)
+
+	file, err := os.Open(f)
+	if err != nil {
+		logger.Error("Ошибка открытия файла", "err", err)
+		return stats, err
+	}
+	defer file.Close()
+
+	fi, err := file.Stat()
+	if err != nil {
+		logger.Error("Ошибка при получении размера файла", "err", err)
+		return stats, err
+	}
+
+	writer, err := l.Sink.Begin(Table{Name: table, File: f, Size: fi.Size(), Version: l.Version})
+	if err != nil {
+		logger.Error("Ошибка при подготовке таблицы", "err", err)
+		return stats, err
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			if aerr := writer.Abort(); aerr != nil {
+				logger.Error("Ошибка при отмене загрузки", "err", aerr)
+			}
+		}
+	}()
+
+	var input io.Reader = file
+	if l.Progress != nil {
+		var finish func()
+		input, finish = l.Progress(file, fi.Size(), logger)
+		defer finish()
+	}
+	reader := newReader(input)
+	rows := []Row{}
+	var total int64
+	quarantine := l.newQuarantine(table)
+	defer quarantine.Close()
+	for {
+		row, err := reader.NextRow()
+		if err == io.EOF {
+			break
+		}
+
+		var decodeErr *DecodeError
+		if errors.As(err, &decodeErr) {
+			switch l.OnError {
+			case PolicySkip:
+				logger.Warn("Запись пропущена", "element", elementName, "offset", decodeErr.Offset, "err", decodeErr.Err)
+			case PolicyQuarantine:
+				if qerr := quarantine.Add(f, decodeErr.Offset, rawElement(decodeErr.Element), decodeErr.Err); qerr != nil {
+					logger.Error("Ошибка записи в карантин", "offset", decodeErr.Offset, "err", qerr)
+					return stats, qerr
+				}
+				logger.Warn("Запись в карантине", "element", elementName, "offset", decodeErr.Offset, "err", decodeErr.Err)
+			default:
+				err = fmt.Errorf("%s: %w", filepath.Base(f), err)
+				logger.Error("Ошибка при декодировании элемента", "element", elementName, "rows", total, "err", err)
+				return stats, err
+			}
+			stats.Skipped++
+			continue
+		}
+		if err != nil {
+			err = fmt.Errorf("%s: %w", filepath.Base(f), err)
+			logger.Error("Ошибка чтения XML", "offset", reader.InputOffset(), "rows", total, "err", err)
+			return stats, err
+		}
+
+		rows = append(rows, row)
+		total++
+
+		if len(rows) == batchSize {
+			if err := l.writeBatch(writer, rows, quarantine, f, &stats); err != nil {
+				logger.Error("Ошибка при вставке данных", "rows", total, "err", err)
+				return stats, err
+			}
+			rows = []Row{}
+			logger.Debug("Записана пачка", "rows", stats.Rows)
+		}
+	}
+
+	if len(rows) > 0 {
+		if err := l.writeBatch(writer, rows, quarantine, f, &stats); err != nil {
+			logger.Error("Ошибка при вставке данных", "rows", total, "err", err)
+			return stats, err
+		}
+	}
+
+	if err := writer.Commit(); err != nil {
+		logger.Error("Ошибка при завершении загрузки таблицы", "rows", stats.Rows, "err", err)
+		return stats, err
+	}
+	committed = true
+
+	logger.Info("Файл загружен", "rows", stats.Rows, "skipped", stats.Skipped, "quarantined", quarantine.Count())
+
+	return stats, err
+}
+
+// writeBatch - пишем пачку в приемник, отклоненные записи обрабатываем по политике OnError
+func (l *Loader) writeBatch(w TableWriter, rows []Row, q *Quarantine, source string, stats *ParseStats) error {
+	rejected, err := w.Write(rows)
+	if err != nil {
+		return err
+	}
+	if len(rejected) > 0 && l.OnError == PolicyAbort {
+		return rejected[0].Err
+	}
+
+	for _, r := range rejected {
+		if l.OnError == PolicyQuarantine {
+			if qerr := q.Add(source, r.Row.Offset, rawElement(r.Row.Element), r.Err); qerr != nil {
+				return qerr
+			}
+		}
+		l.Logger.Warn("Запись не вставлена", "offset", r.Row.Offset, "policy", l.OnError, "err", r.Err)
+	}
+	stats.Rows += int64(len(rows) - len(rejected))
+	stats.Skipped += int64(len(rejected))
+	return nil
+}
+
+// ParseDir - загружаем в БД все файлы ФИАС каталога dir: известные по Registry,
+// остальные - по XSD схемам из архива (если включено Schemas); нераспознанные перечисляются в итоге
+func (l *Loader) ParseDir(dir string) error {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	schemas, err := LoadSchemas(dir, l.Logger)
+	if err != nil {
+		l.Logger.Warn("Ошибка чтения XSD схем, загружаем только известные файлы", "dir", dir, "err", err)
+		schemas = map[string]*Schema{}
+	}
+
+	var report ParseStats
+	var parsed, failed int
+	var unknown []string
+	loaded := map[string]bool{}
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() || strings.EqualFold(filepath.Ext(name), ".xsd") {
+			continue
+		}
+
+		kind, ok := Lookup(name)
+		schema := schemas[FilePrefix(name)]
+		switch {
+		case ok && schema != nil:
+			l.checkSchema(kind, schema)
+		case !ok && schema != nil && l.Schemas:
+			kind, ok = SchemaKind{Schema: schema}, true
+			l.Logger.Info("Файл загружается по XSD схеме", "file", name, "schema", filepath.Base(schema.File), "table", kind.Table())
+		}
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+
+		stats, err := kind.Load(l, filepath.Join(dir, name))
+
+		parsed++
+		loaded[kind.Table()] = true
+		report.Rows += stats.Rows
+		report.Skipped += stats.Skipped
+		if err != nil {
+			failed++
+		}
+	}
+
+	var missing []string
+	for _, kind := range Registry {
+		if !loaded[kind.Table()] {
+			missing = append(missing, kind.Table())
+		}
+	}
+	if len(missing) > 0 {
+		l.Logger.Info("В каталоге нет файлов для таблиц", "tables", missing)
+	}
+
+	l.Logger.Info("Итог загрузки", "files", parsed, "failed", failed, "unknown", len(unknown), "rows", report.Rows, "skipped", report.Skipped)
+	if len(unknown) > 0 {
+		l.Logger.Warn("Нераспознанные файлы не загружены: нет шаблона в Registry и XSD схемы", "files", unknown)
+	}
+	if failed > 0 {
+		return fmt.Errorf("не загружено файлов: %d из %d", failed, parsed)
+	}
+	if len(unknown) > 0 && l.FailOnUnknown {
+		return fmt.Errorf("нераспознанные файлы: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// columnKey - имя атрибута или колонки для сравнения: колонки записей пишутся без "_"
+// и в нижнем регистре (KOD_T_ST -> kodtst)
+func columnKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// checkSchema - предупреждаем об атрибутах XSD, которых нет в колонках записи (новая версия формата)
+
+func (l *Loader) checkSchema(kind FileKind, schema *Schema) {
+	c, ok := kind.(interface{ Columns() []string })
+	if !ok {
+		return
+	}
+
+	columns := map[string]bool{}
+	for _, column := range c.Columns() {
+		columns[columnKey(column)] = true
+	}
+	var extra []string
+	for _, a := range schema.Attributes {
+		if !columns[columnKey(a.Name)] {
+			extra = append(extra, a.Name)
+		}
+	}
+	if len(extra) > 0 {
+		l.Logger.Warn("В XSD схеме есть атрибуты, которые не загружаются", "table", kind.Table(), "schema", filepath.Base(schema.File), "attributes", extra)
+	}
+}
@@ -0,0 +1,27 @@
+package loader
+
+import "regexp"
+
+var ACTSTAT_PATTERN = regexp.MustCompile("^(AS_ACTSTAT_)[0-9]{8}_.+")
+var ADDROBJ_PATTERN = regexp.MustCompile("^(AS_ADDROBJ_)[0-9]{8}_.+")
+var CENTERST_PATTERN = regexp.MustCompile("^(AS_CENTERST_)[0-9]{8}_.+")
+var CURENTST_PATTERN = regexp.MustCompile("^(AS_CURENTST_)[0-9]{8}_.+")
+var DEL_ADDROBJ_PATTERN = regexp.MustCompile("^(AS_DEL_ADDROBJ_)[0-9]{8}_.+")
+var DEL_HOUSE_PATTERN = regexp.MustCompile("^(AS_DEL_HOUSE_)[0-9]{8}_.+")
+var DEL_HOUSEINT_PATTERN = regexp.MustCompile("^(AS_DEL_HOUSEINT_)[0-9]{8}_.+")
+var DEL_NORMDOC_PATTERN = regexp.MustCompile("^(AS_DEL_NORMDOC_)[0-9]{8}_.+")
+var ESTSTAT_PATTERN = regexp.MustCompile("^(AS_ESTSTAT_)[0-9]{8}_.+")
+var FLATTYPE_PATTERN = regexp.MustCompile("^(AS_FLATTYPE_)[0-9]{8}_.+")
+var HOUSE_PATTERN = regexp.MustCompile("^(AS_HOUSE_)[0-9]{8}_.+")
+var HOUSEINT_PATTERN = regexp.MustCompile("^(AS_HOUSEINT_)[0-9]{8}_.+")
+var HSTSTAT_PATTERN = regexp.MustCompile("^(AS_HSTSTAT_)[0-9]{8}_.+")
+var INTVSTAT_PATTERN = regexp.MustCompile("^(AS_INTVSTAT_)[0-9]{8}_.+")
+var LANDMARK_PATTERN = regexp.MustCompile("^(AS_LANDMARK_)[0-9]{8}_.+")
+var NDOCTYPE_PATTERN = regexp.MustCompile("^(AS_NDOCTYPE_)[0-9]{8}_.+")
+var NORMDOC_PATTERN = regexp.MustCompile("^(AS_NORMDOC_)[0-9]{8}_.+")
+var OPERSTAT_PATTERN = regexp.MustCompile("^(AS_OPERSTAT_)[0-9]{8}_.+")
+var SOCRBASE_PATTERN = regexp.MustCompile("^(AS_SOCRBASE_)[0-9]{8}_.+")
+var STRSTAT_PATTERN = regexp.MustCompile("^(AS_STRSTAT_)[0-9]{8}_.+")
+var STEAD_PATTERN = regexp.MustCompile("^(AS_STEAD_)[0-9]{8}_.+")
+var ROOM_PATTERN = regexp.MustCompile("^(AS_ROOM_)[0-9]{8}_.+")
+var ROOMTYPE_PATTERN = regexp.MustCompile("^(AS_ROOMTYPE_)[0-9]{8}_.+")
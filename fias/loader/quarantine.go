@@ -0,0 +1,125 @@
+package loader
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/doug-martin/goqu.v3"
+)
+
+// Политики обработки записей, которые не удалось разобрать или вставить
+const (
+	PolicyAbort      = "abort"      // прервать загрузку файла
+	PolicySkip       = "skip"       // пропустить запись и записать в лог
+	PolicyQuarantine = "quarantine" // пропустить запись и сохранить ее в карантин
+)
+
+// Куда сохранять записи карантина
+const (
+	QuarantineFile  = "file"
+	QuarantineTable = "table"
+)
+
+// Quarantine - карантин записей загрузки одной таблицы
+type Quarantine struct {
+	table  string
+	loader *Loader
+	gq     *goqu.Database
+	file   *os.File
+	ready  bool
+	count  int64
+}
+
+// newQuarantine - карантин для таблицы table, файл или таблица создаются при первой записи
+func (l *Loader) newQuarantine(table string) *Quarantine {
+	return &Quarantine{table: table, loader: l}
+}
+
+// Add - сохраняем исходный элемент raw и ошибку
+func (q *Quarantine) Add(source string, offset int64, raw string, cause error) error {
+	if q.loader.QuarantineStorage == QuarantineTable {
+		return q.addToTable(source, offset, raw, cause)
+	}
+
+	if q.file == nil {
+		if err := os.MkdirAll(q.loader.QuarantineDir, 0755); err != nil {
+			return err
+		}
+		name := filepath.Join(q.loader.QuarantineDir, fmt.Sprintf("%s_%d_%s.xml", q.table, q.loader.Version, time.Now().Format("20060102150405")))
+		file, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		q.file = file
+	}
+
+	q.count++
+	comment := strings.ReplaceAll(fmt.Sprintf("%s:%d: %v", filepath.Base(source), offset, cause), "--", "- -")
+	_, err := fmt.Fprintf(q.file, "<!-- %s -->\n%s\n", comment, raw)
+	return err
+}
+
+// addToTable - запись в таблицу quarantine, таблица создается при первой записи
+func (q *Quarantine) addToTable(source string, offset int64, raw string, cause error) error {
+	if q.loader.DB == nil {
+		return errors.New("карантин в таблице недоступен без подключения к БД")
+	}
+	if !q.ready {
+		q.gq = goqu.New("postgres", q.loader.DB)
+		_, err := q.gq.Exec(`CREATE TABLE IF NOT EXISTS quarantine (
+	id serial PRIMARY KEY,
+	version_id integer,
+	table_name text NOT NULL,
+	file text NOT NULL,
+	byte_offset bigint,
+	element text NOT NULL,
+	error text NOT NULL,
+	created_at timestamp with time zone NOT NULL
+);`)
+		if err != nil {
+			return err
+		}
+		q.ready = true
+	}
+
+	q.count++
+	_, err := q.gq.From("quarantine").Insert(goqu.Record{
+		"version_id":  q.loader.Version,
+		"table_name":  q.table,
+		"file":        filepath.Base(source),
+		"byte_offset": offset,
+		"element":     raw,
+		"error":       cause.Error(),
+		"created_at":  time.Now(),
+	}).Exec()
+	return err
+}
+
+// Count - кол-во записей в карантине
+func (q *Quarantine) Count() int64 {
+	return q.count
+}
+
+// Close - закрываем файл карантина
+func (q *Quarantine) Close() error {
+	if q.file == nil {
+		return nil
+	}
+	return q.file.Close()
+}
+
+// rawElement - восстанавливаем XML элемент записи по его атрибутам
+func rawElement(se xml.StartElement) string {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	enc.EncodeToken(se)
+	enc.EncodeToken(se.End())
+	enc.Flush()
+	return buf.String()
+}
@@ -0,0 +1,51 @@
+package loader
+
+import (
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestQuarantine(t *testing.T) {
+	l := New(nil)
+	l.QuarantineDir = filepath.Join(t.TempDir(), "quarantine")
+
+	q := l.newQuarantine("house")
+	se := xml.StartElement{
+		Name: xml.Name{Local: "House"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "HOUSENUM"}, Value: `1 "а"`}, {Name: xml.Name{Local: "ENDDATE"}, Value: "никогда"}},
+	}
+	if err := q.Add("/tmp/AS_HOUSE_20190514_b.XML", 42, rawElement(se), errors.New("parsing time -- bad")); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if q.Count() != 1 {
+		t.Errorf("в карантине %d записей, ожидается 1", q.Count())
+	}
+
+	files, err := filepath.Glob(filepath.Join(l.QuarantineDir, "house_*.xml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("файлы карантина %v, %v", files, err)
+	}
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "<!-- AS_HOUSE_20190514_b.XML:42: parsing time - - bad -->\n" +
+		`<House HOUSENUM="1 &#34;а&#34;" ENDDATE="никогда"></House>` + "\n"
+	if string(data) != want {
+		t.Errorf("карантин:\n%s\nожидается:\n%s", data, want)
+	}
+
+	// пустой карантин не создает файл
+	if err := l.newQuarantine("stead").Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := filepath.Glob(filepath.Join(l.QuarantineDir, "stead_*")); len(files) != 0 {
+		t.Errorf("созданы файлы %v", files)
+	}
+}
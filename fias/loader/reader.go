@@ -0,0 +1,106 @@
+package loader
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// DecodeError - ошибка разбора одной записи, после нее чтение можно продолжить
+type DecodeError struct {
+	Offset  int64
+	Element xml.StartElement
+	Err     error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("смещение %d: %v", e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Reader - потоковое чтение записей типа T из XML файла ФИАС
+type Reader[T model.Record] struct {
+	decoder *xml.Decoder
+	element string
+	offset  int64
+	start   xml.StartElement
+}
+
+// NewReader - читаем из r записи T, имя элемента берется из T.Element()
+func NewReader[T model.Record](r io.Reader) *Reader[T] {
+	var zero T
+	return &Reader[T]{decoder: xml.NewDecoder(r), element: zero.Element()}
+}
+
+// Next - следующая запись.
+// В конце файла возвращает io.EOF, при ошибке в записи - *DecodeError,
+// при любой другой ошибке продолжать чтение нельзя.
+func (r *Reader[T]) Next() (T, error) {
+	for {
+		var v T
+		t, err := r.decoder.Token()
+		if err == io.EOF {
+			return v, io.EOF
+		}
+		if err != nil {
+			return v, fmt.Errorf("смещение %d: %w", r.decoder.InputOffset(), err)
+		}
+
+		se, ok := t.(xml.StartElement)
+		if !ok || se.Name.Local != r.element {
+			continue
+		}
+
+		r.offset = r.decoder.InputOffset()
+		r.start = se.Copy()
+		if err := r.decoder.DecodeElement(&v, &se); err != nil {
+			return v, &DecodeError{Offset: r.offset, Element: r.start, Err: err}
+		}
+		return v, nil
+	}
+}
+
+// NextRow - следующая запись со значениями колонок и исходным элементом
+func (r *Reader[T]) NextRow() (Row, error) {
+	v, err := r.Next()
+	if err != nil {
+		return Row{}, err
+	}
+	return Row{Record: v, Values: v.Row(), Element: r.start, Offset: r.offset}, nil
+}
+
+// All - итератор по записям; после ошибки итерация заканчивается, кроме *DecodeError
+func (r *Reader[T]) All() func(yield func(T, error) bool) {
+	return func(yield func(T, error) bool) {
+		for {
+			v, err := r.Next()
+			if err == io.EOF {
+				return
+			}
+			_, recoverable := err.(*DecodeError)
+			if !yield(v, err) || (err != nil && !recoverable) {
+				return
+			}
+		}
+	}
+}
+
+// Offset - смещение в файле последней прочитанной записи
+func (r *Reader[T]) Offset() int64 {
+	return r.offset
+}
+
+// Element - исходный элемент последней прочитанной записи
+func (r *Reader[T]) Element() xml.StartElement {
+	return r.start
+}
+
+// InputOffset - сколько байт файла прочитано
+func (r *Reader[T]) InputOffset() int64 {
+	return r.decoder.InputOffset()
+}
@@ -0,0 +1,90 @@
+package loader
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+func TestReaderDecodeErrors(t *testing.T) {
+	const data = `<?xml version="1.0" encoding="utf-8"?>
+<ActualStatuses>
+<ActualStatus ACTSTATID="0" NAME="Не актуальный" />
+<ActualStatus ACTSTATID="один" NAME="Ошибка" />
+<ActualStatus ACTSTATID="1" NAME="Актуальный" />
+</ActualStatuses>`
+
+	r := NewReader[model.ActualStatus](strings.NewReader(data))
+
+	v, err := r.Next()
+	if err != nil || v.ACTSTATID != 0 || v.NAME != "Не актуальный" {
+		t.Fatalf("первая запись %+v, %v", v, err)
+	}
+
+	_, err = r.Next()
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) {
+		t.Fatalf("ошибка %v, ожидается *DecodeError", err)
+	}
+	if decodeErr.Element.Name.Local != "ActualStatus" || decodeErr.Offset == 0 || decodeErr.Offset != r.Offset() {
+		t.Errorf("DecodeError %+v, смещение записи %d", decodeErr, r.Offset())
+	}
+	if attr := decodeErr.Element.Attr; len(attr) != 2 || attr[0].Value != "один" {
+		t.Errorf("исходный элемент %+v", decodeErr.Element)
+	}
+
+	// после ошибки в записи чтение продолжается
+	v, err = r.Next()
+	if err != nil || v.ACTSTATID != 1 || v.NAME != "Актуальный" {
+		t.Fatalf("третья запись %+v, %v", v, err)
+	}
+
+	if _, err := r.Next(); err != io.EOF {
+		t.Fatalf("ошибка %v, ожидается io.EOF", err)
+	}
+}
+
+func TestReaderBrokenXML(t *testing.T) {
+	const data = `<ActualStatuses><ActualStatus ACTSTATID="0" NAME="a" /><ActualStatus ACTSTATID="1 NAME="b" />`
+
+	var values []model.ActualStatus
+	var errs []error
+	for v, err := range NewReader[model.ActualStatus](strings.NewReader(data)).All() {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		values = append(values, v)
+	}
+
+	if len(values) != 1 || len(errs) != 1 {
+		t.Fatalf("записей %d, ошибок %d: %v", len(values), len(errs), errs)
+	}
+	var decodeErr *DecodeError
+	if errors.As(errs[0], &decodeErr) {
+		t.Errorf("ошибка разметки %v не должна быть *DecodeError", errs[0])
+	}
+}
+
+func TestReaderAllContinuesAfterDecodeError(t *testing.T) {
+	const data = `<ActualStatuses>
+<ActualStatus ACTSTATID="x" /><ActualStatus ACTSTATID="2" /><ActualStatus ACTSTATID="y" /><ActualStatus ACTSTATID="3" />
+</ActualStatuses>`
+
+	var ids []int
+	failed := 0
+	for v, err := range NewReader[model.ActualStatus](strings.NewReader(data)).All() {
+		if err != nil {
+			failed++
+			continue
+		}
+		ids = append(ids, v.ACTSTATID)
+	}
+
+	if failed != 2 || len(ids) != 2 || ids[0] != 2 || ids[1] != 3 {
+		t.Errorf("записи %v, ошибок %d", ids, failed)
+	}
+}
@@ -0,0 +1,82 @@
+package loader
+
+import (
+	"regexp"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// FileKind - вид файла ФИАС: шаблон имени, таблица и загрузка
+type FileKind interface {
+	Match(name string) bool
+	Table() string
+	Load(l *Loader, path string) (ParseStats, error)
+}
+
+// Kind - файл с записями T, загружаемый в таблицу TableName
+type Kind[T model.Record] struct {
+	Pattern   *regexp.Regexp
+	TableName string
+}
+
+// Match - подходит ли имя файла
+func (k Kind[T]) Match(name string) bool {
+	return k.Pattern.MatchString(name)
+}
+
+// Table - таблица для записей
+func (k Kind[T]) Table() string {
+	return k.TableName
+}
+
+// Columns - колонки таблицы
+func (k Kind[T]) Columns() []string {
+	var zero T
+	columns := make([]string, 0, len(zero.Row()))
+	for column := range zero.Row() {
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// Load - загружаем файл path в таблицу
+func (k Kind[T]) Load(l *Loader, path string) (ParseStats, error) {
+	return Parse[T](l, path, k.TableName)
+}
+
+// Registry - известные файлы ФИАС
+var Registry = []FileKind{
+	Kind[model.ActualStatus]{ACTSTAT_PATTERN, "actual_status"},
+	Kind[model.Object]{ADDROBJ_PATTERN, "address_objects"},
+	Kind[model.CenterStatus]{CENTERST_PATTERN, "center_status"},
+	Kind[model.CurrentStatus]{CURENTST_PATTERN, "current_status"},
+	Kind[model.Object]{DEL_ADDROBJ_PATTERN, "del_address_objects"},
+	Kind[model.House]{DEL_HOUSE_PATTERN, "del_house"},
+	Kind[model.HouseInterval]{DEL_HOUSEINT_PATTERN, "del_house_interval"},
+	Kind[model.NormativeDocument]{DEL_NORMDOC_PATTERN, "del_normative_document"},
+	Kind[model.EstateStatus]{ESTSTAT_PATTERN, "estate_status"},
+	Kind[model.FlatType]{FLATTYPE_PATTERN, "flat_type"},
+	Kind[model.House]{HOUSE_PATTERN, "house"},
+	Kind[model.HouseInterval]{HOUSEINT_PATTERN, "house_interval"},
+	Kind[model.HouseStateStatus]{HSTSTAT_PATTERN, "house_state_status"},
+	Kind[model.IntervalStatus]{INTVSTAT_PATTERN, "interval_status"},
+	Kind[model.Landmark]{LANDMARK_PATTERN, "landmark"},
+	Kind[model.NormativeDocumentType]{NDOCTYPE_PATTERN, "normative_document_type"},
+	Kind[model.NormativeDocument]{NORMDOC_PATTERN, "normative_document"},
+	Kind[model.OperationStatus]{OPERSTAT_PATTERN, "operation_status"},
+	Kind[model.AddressObjectType]{SOCRBASE_PATTERN, "address_object_type"},
+	Kind[model.StructureStatus]{STRSTAT_PATTERN, "structure_status"},
+	Kind[model.Stead]{STEAD_PATTERN, "steads"},
+	Kind[model.Room]{ROOM_PATTERN, "rooms"},
+	Kind[model.RoomType]{ROOMTYPE_PATTERN, "room_type"},
+}
+
+// Lookup - вид файла по имени
+func Lookup(name string) (FileKind, bool) {
+	for _, kind := range Registry {
+		if kind.Match(name) {
+			return kind, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,41 @@
+package loader
+
+import (
+	"fmt"
+)
+
+// DefaultMaxDropPercent - на сколько процентов может уменьшиться таблица при замене
+const DefaultMaxDropPercent = 20.0
+
+// maxDrop - допустимое уменьшение таблицы в процентах; 100 и больше - проверка выключена
+func (s *PostgresSink) maxDrop(table string) float64 {
+	if limit, ok := s.TableMaxDropPercent[table]; ok {
+		return limit
+	}
+	return s.MaxDropPercent
+}
+
+// checkSanity - проверяем загруженное кол-во строк перед заменой таблицы
+func (w *postgresWriter) checkSanity() error {
+	table, rows := w.table, w.rows
+	limit := w.sink.maxDrop(table)
+	if limit >= 100 {
+		return nil
+	}
+
+	var current int64
+	if err := w.sink.DB.QueryRow("SELECT count(*) FROM " + table + ";").Scan(&current); err != nil {
+		return fmt.Errorf("подсчет строк %s: %w", table, err)
+	}
+	if current == 0 || rows >= current {
+		return nil
+	}
+
+	drop := float64(current-rows) * 100 / float64(current)
+	w.logger.Debug("Проверка кол-ва строк", "table", table, "current", current, "rows", rows, "drop_percent", drop, "limit", limit)
+	if drop > limit {
+		return fmt.Errorf("кол-во строк %s уменьшилось на %.1f%% (%d -> %d), допустимо %.1f%%", table, drop, current, rows, limit)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package loader
+
+import (
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCheckSanity(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   float64
+		tables  map[string]float64
+		current int64 // строк в текущей таблице, -1 - таблицу не считаем
+		rows    int64
+		err     string
+	}{
+		{"пустая текущая таблица", 20, nil, 0, 0, ""},
+		{"новая версия пуста", 20, nil, 1000, 0, "уменьшилось на 100.0%"},
+		{"рост", 20, nil, 1000, 1500, ""},
+		{"ровно на пороге", 20, nil, 1000, 800, ""},
+		{"выше порога", 20, nil, 1000, 799, "уменьшилось на 20.1%"},
+		{"порог таблицы", 20, map[string]float64{"house": 50}, 1000, 600, ""},
+		{"порог таблицы строже", 20, map[string]float64{"house": 5}, 1000, 900, "допустимо 5.0%"},
+		{"проверка выключена", 100, nil, -1, 0, ""},
+		{"выключена для таблицы", 20, map[string]float64{"house": 100}, -1, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			if tt.current >= 0 {
+				mock.ExpectQuery(`SELECT count\(\*\) FROM house;`).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tt.current))
+			}
+
+			sink := &PostgresSink{DB: db, MaxDropPercent: tt.limit, TableMaxDropPercent: tt.tables}
+			w := &postgresWriter{sink: sink, table: "house", target: "temp_house", rows: tt.rows, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+			err = w.checkSanity()
+			if tt.err == "" && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)) {
+				t.Fatalf("ошибка %v, ожидается %q", err, tt.err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+// TestBeginStaging - небольшие таблицы грузятся через временную, пока проверка кол-ва
+// строк включена; с порогом 100% - сразу в таблицу через TRUNCATE
+func TestBeginStaging(t *testing.T) {
+	tests := []struct {
+		name   string
+		limit  float64
+		tables map[string]float64
+		query  string
+	}{
+		{"проверка включена", 20, nil, `CREATE TABLE temp_actstat \( like actstat including all\);`},
+		{"порог 100", 100, nil, `TRUNCATE actstat;`},
+		{"порог 100 для таблицы", 20, map[string]float64{"actstat": 100}, `TRUNCATE actstat;`},
+		{"порог 100 для другой таблицы", 20, map[string]float64{"house": 100}, `CREATE TABLE temp_actstat`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			mock.ExpectExec(tt.query).WillReturnResult(sqlmock.NewResult(0, 0))
+
+			sink := NewPostgresSink(db)
+			sink.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+			sink.MaxDropPercent = tt.limit
+			if tt.tables != nil {
+				sink.TableMaxDropPercent = tt.tables
+			}
+			if _, err := sink.Begin(Table{Name: "actstat", Size: 1024, Version: 634}); err != nil {
+				t.Fatal(err)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package loader
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// SchemaKind - файл без описания в Registry, загружаемый по XSD схеме из архива
+// в таблицу Schema.TableName(); все значения пишутся строками, пустые - NULL
+type SchemaKind struct {
+	Schema *Schema
+}
+
+// Match - подходит ли имя файла
+func (k SchemaKind) Match(name string) bool {
+	return FilePrefix(name) == k.Schema.Prefix
+}
+
+// Table - таблица для записей
+func (k SchemaKind) Table() string {
+	return k.Schema.TableName()
+}
+
+// Load - создаем таблицу по схеме, если ее нет, и загружаем файл path
+func (k SchemaKind) Load(l *Loader, path string) (ParseStats, error) {
+	if l.DB != nil {
+		added, err := k.createTable(l.DB)
+		if err != nil {
+			return ParseStats{}, err
+		}
+		if len(added) > 0 {
+			l.Logger.Info("В таблицу добавлены колонки из XSD схемы", "table", k.Table(), "schema", filepath.Base(k.Schema.File), "columns", added)
+		}
+	}
+	return l.parse(path, k.Table(), k.Schema.Element, func(r io.Reader) rowReader {
+		return &schemaReader{decoder: xml.NewDecoder(r), schema: k.Schema}
+	})
+}
+
+// createTable - таблица с колонками по атрибутам схемы; в уже созданную таблицу добавляем
+// колонки новых атрибутов (без NOT NULL - в прежних записях их нет), added - добавленные колонки
+func (k SchemaKind) createTable(db *sql.DB) (added []string, err error) {
+	columns := make([]string, len(k.Schema.Attributes))
+	for i, a := range k.Schema.Attributes {
+		columns[i] = pq.QuoteIdentifier(strings.ToLower(a.Name)) + " " + a.ColumnType()
+		if a.Required {
+			columns[i] += " NOT NULL"
+		}
+	}
+
+	table := pq.QuoteIdentifier(k.Table())
+	_, err = db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n);", table, strings.Join(columns, ",\n\t")))
+	if err != nil {
+		return nil, fmt.Errorf("создание таблицы %s по схеме %s: %w", k.Table(), k.Schema.File, err)
+	}
+
+	rows, err := db.Query("SELECT column_name FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = $1;", k.Table())
+	if err != nil {
+		return nil, fmt.Errorf("колонки таблицы %s: %w", k.Table(), err)
+	}
+	defer rows.Close()
+	existing := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		existing[name] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var alter []string
+	for _, a := range k.Schema.Attributes {
+		column := strings.ToLower(a.Name)
+		if !existing[column] {
+			added = append(added, column)
+			alter = append(alter, "ADD COLUMN IF NOT EXISTS "+pq.QuoteIdentifier(column)+" "+a.ColumnType())
+		}
+	}
+	if len(alter) == 0 {
+		return nil, nil
+	}
+	if _, err := db.Exec(fmt.Sprintf("ALTER TABLE %s %s;", table, strings.Join(alter, ", "))); err != nil {
+		return nil, fmt.Errorf("таблица %s: не добавлены колонки %s из схемы %s: %w", k.Table(), strings.Join(added, ", "), k.Schema.File, err)
+	}
+	return added, nil
+}
+
+// schemaReader - чтение записей по схеме: атрибуты элемента в колонки
+type schemaReader struct {
+	decoder *xml.Decoder
+	schema  *Schema
+}
+
+// NextRow - следующая запись; атрибуты, которых нет в схеме, считаются ошибкой записи
+func (r *schemaReader) NextRow() (Row, error) {
+	for {
+		t, err := r.decoder.Token()
+		if err == io.EOF {
+			return Row{}, io.EOF
+		}
+		if err != nil {
+			return Row{}, fmt.Errorf("смещение %d: %w", r.decoder.InputOffset(), err)
+		}
+
+		se, ok := t.(xml.StartElement)
+		if !ok || se.Name.Local != r.schema.Element {
+			continue
+		}
+
+		row := Row{Values: map[string]interface{}{}, Element: se.Copy(), Offset: r.decoder.InputOffset()}
+		for _, a := range r.schema.Attributes {
+			row.Values[strings.ToLower(a.Name)] = nil
+		}
+		for _, attr := range se.Attr {
+			column := strings.ToLower(attr.Name.Local)
+			if _, ok := row.Values[column]; !ok {
+				return Row{}, &DecodeError{Offset: row.Offset, Element: row.Element, Err: fmt.Errorf("атрибут %s отсутствует в схеме", attr.Name.Local)}
+			}
+			if attr.Value != "" {
+				row.Values[column] = attr.Value
+			}
+		}
+		return row, nil
+	}
+}
+
+// InputOffset - сколько байт файла прочитано
+func (r *schemaReader) InputOffset() int64 {
+	return r.decoder.InputOffset()
+}
@@ -0,0 +1,82 @@
+package loader
+
+import (
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCreateTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	kind := SchemaKind{Schema: &Schema{
+		Prefix:  "AS_FLATTYPE",
+		Element: "FlatType",
+		File:    "AS_FLATTYPE_2_250_08_04_01_01.xsd",
+		Attributes: []SchemaAttribute{
+			{Name: "FLTYPEID", Type: "integer", Required: true},
+			{Name: "NAME", Type: "string", Required: true},
+			{Name: "SHORTNAME", Type: "string"},
+			{Name: "UPDATEDATE", Type: "date", Required: true},
+		},
+	}}
+	columns := func(names ...string) *sqlmock.Rows {
+		rows := sqlmock.NewRows([]string{"column_name"})
+		for _, name := range names {
+			rows.AddRow(name)
+		}
+		return rows
+	}
+
+	// новая таблица: все колонки созданы
+	expectExec(mock, `CREATE TABLE IF NOT EXISTS "flattype" (
+	"fltypeid" bigint NOT NULL,
+	"name" text NOT NULL,
+	"shortname" text,
+	"updatedate" date NOT NULL
+);`)
+	mock.ExpectQuery(`FROM information_schema.columns`).WithArgs("flattype").
+		WillReturnRows(columns("fltypeid", "name", "shortname", "updatedate"))
+
+	if added, err := kind.createTable(db); err != nil || len(added) != 0 {
+		t.Errorf("добавлены %v, ошибка %v", added, err)
+	}
+
+	// таблица прежней версии схемы: новые атрибуты добавляются колонками без NOT NULL
+	expectExec(mock, `CREATE TABLE IF NOT EXISTS "flattype"`)
+	mock.ExpectQuery(`FROM information_schema.columns`).WithArgs("flattype").
+		WillReturnRows(columns("fltypeid", "name"))
+	expectExec(mock, `ALTER TABLE "flattype" ADD COLUMN IF NOT EXISTS "shortname" text, ADD COLUMN IF NOT EXISTS "updatedate" date;`)
+
+	added, err := kind.createTable(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"shortname", "updatedate"}; !slices.Equal(added, want) {
+		t.Errorf("добавлены %v, ожидается %v", added, want)
+	}
+
+	// колонки не добавить: ошибка называет их
+	failed := errors.New("permission denied")
+	expectExec(mock, `CREATE TABLE IF NOT EXISTS "flattype"`)
+	mock.ExpectQuery(`FROM information_schema.columns`).WithArgs("flattype").
+		WillReturnRows(columns("fltypeid", "name", "shortname"))
+	mock.ExpectExec(regexp.QuoteMeta(`ALTER TABLE "flattype"`)).WillReturnError(failed)
+
+	_, err = kind.createTable(db)
+	if !errors.Is(err, failed) || !strings.Contains(err.Error(), "updatedate") {
+		t.Errorf("ошибка %v, ожидается с колонкой updatedate", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
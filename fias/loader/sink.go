@@ -0,0 +1,114 @@
+package loader
+
+import (
+	"encoding/xml"
+	"errors"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// Table - загружаемая таблица
+type Table struct {
+	Name    string
+	File    string // исходный файл
+	Size    int64  // размер исходного файла
+	Version int    // версия ФИАС
+}
+
+// Row - запись для приемника: значения колонок и исходный элемент XML
+type Row struct {
+	Record  model.Record
+	Values  map[string]interface{}
+	Element xml.StartElement
+	Offset  int64
+}
+
+// Rejected - запись, которую приемник не принял
+type Rejected struct {
+	Row Row
+	Err error
+}
+
+// Sink - приемник записей, загрузка каждой таблицы начинается с Begin
+type Sink interface {
+	Begin(t Table) (TableWriter, error)
+}
+
+// TableWriter - загрузка одной таблицы: Write пачками, затем Commit или Abort
+type TableWriter interface {
+	// Write - записываем пачку, возвращаем записи, которые не удалось записать;
+	// ошибка означает, что продолжать загрузку таблицы нельзя
+	Write(rows []Row) ([]Rejected, error)
+	// Commit - фиксируем загрузку; после ошибки загрузчик вызывает Abort
+	Commit() error
+	// Abort - отменяем загрузку, повторный вызов ничего не делает
+	Abort() error
+}
+
+// NopSink - приемник, который ничего не сохраняет (проверка файлов)
+type NopSink struct{}
+
+type nopWriter struct{}
+
+// Begin - начало загрузки таблицы
+func (NopSink) Begin(t Table) (TableWriter, error) { return nopWriter{}, nil }
+
+func (nopWriter) Write(rows []Row) ([]Rejected, error) { return nil, nil }
+func (nopWriter) Commit() error                        { return nil }
+func (nopWriter) Abort() error                         { return nil }
+
+// MultiSink - пишет одни и те же записи в несколько приемников за один проход
+type MultiSink []Sink
+
+// multiWriter - загрузка таблицы во все приемники; Commit фиксирует их по порядку,
+// Abort отменяет те, что еще не зафиксированы
+type multiWriter struct {
+	writers   []TableWriter
+	committed int
+}
+
+// Begin - начинаем загрузку таблицы во всех приемниках
+func (m MultiSink) Begin(t Table) (TableWriter, error) {
+	w := &multiWriter{writers: make([]TableWriter, 0, len(m))}
+	for _, sink := range m {
+		tw, err := sink.Begin(t)
+		if err != nil {
+			w.Abort()
+			return nil, err
+		}
+		w.writers = append(w.writers, tw)
+	}
+	return w, nil
+}
+
+func (m *multiWriter) Write(rows []Row) ([]Rejected, error) {
+	var rejected []Rejected
+	for _, w := range m.writers {
+		r, err := w.Write(rows)
+		rejected = append(rejected, r...)
+		if err != nil {
+			return rejected, err
+		}
+	}
+	return rejected, nil
+}
+
+// Commit - при ошибке приемника следующие не фиксируются, их отменит Abort;
+// уже зафиксированные приемники остаются с новой версией
+func (m *multiWriter) Commit() error {
+	for _, w := range m.writers[m.committed:] {
+		if err := w.Commit(); err != nil {
+			return err
+		}
+		m.committed++
+	}
+	return nil
+}
+
+func (m *multiWriter) Abort() error {
+	var errs []error
+	for _, w := range m.writers[m.committed:] {
+		errs = append(errs, w.Abort())
+	}
+	return errors.Join(errs...)
+}
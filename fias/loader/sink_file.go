@@ -0,0 +1,66 @@
+package loader
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// FileSink - выгрузка записей в файлы JSON Lines <Dir>/<таблица>.jsonl
+type FileSink struct {
+	Dir string
+}
+
+type fileWriter struct {
+	file *os.File
+	buf  *bufio.Writer
+	enc  *json.Encoder
+	path string
+}
+
+// Begin - создаем временный файл таблицы, он заменит прежний только при Commit
+func (s *FileSink) Begin(t Table) (TableWriter, error) {
+	if err := os.MkdirAll(s.Dir, 0755); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(s.Dir, t.Name+".jsonl")
+	file, err := os.Create(path + ".tmp")
+	if err != nil {
+		return nil, fmt.Errorf("создание файла выгрузки %s: %w", t.Name, err)
+	}
+
+	buf := bufio.NewWriter(file)
+	return &fileWriter{file: file, buf: buf, enc: json.NewEncoder(buf), path: path}, nil
+}
+
+func (w *fileWriter) Write(rows []Row) ([]Rejected, error) {
+	for _, row := range rows {
+		if err := w.enc.Encode(row.Values); err != nil {
+			return nil, fmt.Errorf("запись в %s: %w", w.file.Name(), err)
+		}
+	}
+	return nil, nil
+}
+
+func (w *fileWriter) Commit() error {
+	if err := w.buf.Flush(); err != nil {
+		w.Abort()
+		return err
+	}
+	if err := w.file.Close(); err != nil {
+		os.Remove(w.file.Name())
+		return err
+	}
+	return os.Rename(w.file.Name(), w.path)
+}
+
+func (w *fileWriter) Abort() error {
+	w.file.Close()
+	if err := os.Remove(w.file.Name()); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,209 @@
+package loader
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/lib/pq"
+	"gopkg.in/doug-martin/goqu.v3"
+)
+
+// stagingThreshold - файлы больше этого размера грузятся через временную таблицу
+const stagingThreshold = int64(20388921)
+
+// PostgresSink - приемник PostgreSQL: новая версия грузится во временную таблицу и заменяет
+// текущую после проверки кол-ва строк; через TRUNCATE - только небольшие файлы таблиц
+// с выключенной проверкой
+type PostgresSink struct {
+	DB *sql.DB
+
+	// MaxDropPercent - на сколько процентов может уменьшиться таблица, 100 - без проверки;
+	// TableMaxDropPercent переопределяет порог для отдельных таблиц
+	MaxDropPercent      float64
+	TableMaxDropPercent map[string]float64
+
+	// Diff - перед заменой сравнивать новую версию с текущей (таблицы из DiffKeys
+	// всегда грузятся через временную таблицу) и писать журнал изменений
+	Diff bool
+
+	// DiffOnly - только журнал изменений: таблицы из DiffKeys грузятся во временные
+	// и сравниваются с текущими, остальные пропускаются, текущие данные не меняются
+	DiffOnly bool
+
+	// KeepVersions - сколько предыдущих версий хранить снимками <таблица>_v<версия>
+	// для отката (Rollback); если больше 0, все таблицы грузятся через временную
+	KeepVersions int
+
+	Logger *slog.Logger
+}
+
+type postgresWriter struct {
+	sink    *PostgresSink
+	gq      *goqu.Database
+	table   string
+	target  string
+	version int
+	rows    int64
+	logger  *slog.Logger
+}
+
+// NewPostgresSink - приемник PostgreSQL с порогами по умолчанию
+func NewPostgresSink(db *sql.DB) *PostgresSink {
+	return &PostgresSink{
+		DB:                  db,
+		MaxDropPercent:      DefaultMaxDropPercent,
+		TableMaxDropPercent: map[string]float64{},
+		Logger:              slog.Default(),
+	}
+}
+
+// Begin - создаем временную таблицу или очищаем таблицу, если ее не нужно проверять,
+// сравнивать и сохранять снимком
+func (s *PostgresSink) Begin(t Table) (TableWriter, error) {
+	w := &postgresWriter{
+		sink:    s,
+		gq:      goqu.New("postgres", s.DB),
+		table:   t.Name,
+		target:  t.Name,
+		version: t.Version,
+		logger:  s.Logger.With("table", t.Name),
+	}
+
+	_, diff := DiffKeys[t.Name]
+	if s.DiffOnly && !diff {
+		return nopWriter{}, nil
+	}
+	if s.DiffOnly || t.Size > stagingThreshold || s.maxDrop(t.Name) < 100 || (s.Diff && diff) || s.KeepVersions > 0 {
+		w.target = "temp_" + t.Name
+		w.logger.Info("Создаем временную таблицу", "temp_table", w.target, "size", t.Size)
+		createTemplateTable := fmt.Sprintf("CREATE TABLE %s ( like %s including all);", w.target, t.Name)
+
+		if _, err := s.DB.Exec(createTemplateTable); err != nil {
+			return nil, fmt.Errorf("создание временной таблицы %s: %w", w.target, err)
+		}
+		return w, nil
+	}
+
+	result, err := s.DB.Exec("TRUNCATE " + w.target + ";")
+	if err != nil {
+		return nil, fmt.Errorf("удаление данных из таблицы %s: %w", w.target, err)
+	}
+	rowsAffected, _ := result.RowsAffected()
+	w.logger.Info("Таблица очищена", "rows", rowsAffected)
+
+	return w, nil
+}
+
+// Write - вставляем пачку; при ошибке в данных делим пачку пополам и повторяем
+func (w *postgresWriter) Write(rows []Row) ([]Rejected, error) {
+	rejected, err := bisect(rows, w.insert)
+	w.rows += int64(len(rows) - len(rejected))
+	return rejected, err
+}
+
+// insert - вставляем записи одним запросом
+func (w *postgresWriter) insert(rows []Row) error {
+	records := make([]goqu.Record, len(rows))
+	for i, row := range rows {
+		records[i] = goqu.Record(row.Values)
+	}
+	_, err := w.gq.From(w.target).Insert(records).Exec()
+	return err
+}
+
+// bisect - вставляем rows через insert; при ошибке в данных (isDataError) делим пачку
+// пополам, пока ошибка не сведется к отдельным записям, их возвращаем отклоненными
+func bisect(rows []Row, insert func(rows []Row) error) ([]Rejected, error) {
+	err := insert(rows)
+	if err == nil || !isDataError(err) {
+		return nil, err
+	}
+
+	if len(rows) == 1 {
+		return []Rejected{{Row: rows[0], Err: err}}, nil
+	}
+
+	mid := len(rows) / 2
+	left, err := bisect(rows[:mid], insert)
+	if err != nil {
+		return left, err
+	}
+	right, err := bisect(rows[mid:], insert)
+	return append(left, right...), err
+}
+
+// Commit - проверяем пороги и подменяем таблицу временной
+func (w *postgresWriter) Commit() error {
+	if w.target == w.table {
+		return nil
+	}
+
+	if w.sink.DiffOnly {
+		defer w.Abort()
+		stats, err := DiffTables(w.sink.DB, w.table, w.target, w.version)
+		if err != nil {
+			return err
+		}
+		w.logger.Info("Изменения записаны, таблица не заменяется", "changes_table", ChangesTable(w.table), "added", stats.Added, "changed", stats.Changed, "removed", stats.Removed)
+		return nil
+	}
+
+	if err := w.checkSanity(); err != nil {
+		w.logger.Error("Версия не прошла проверку, оставляем текущие данные", "temp_table", w.target, "rows", w.rows, "err", err)
+		w.Abort()
+		return err
+	}
+
+	if _, ok := DiffKeys[w.table]; ok && w.sink.Diff {
+		stats, err := DiffTables(w.sink.DB, w.table, w.target, w.version)
+		if err != nil {
+			w.Abort()
+			return err
+		}
+		w.logger.Info("Изменения записаны", "changes_table", ChangesTable(w.table), "added", stats.Added, "changed", stats.Changed, "removed", stats.Removed)
+	}
+
+	w.logger.Info("Начинаем переносить данные", "temp_table", w.target)
+	if w.sink.KeepVersions > 0 {
+		snapshot, err := swapKeeping(w.sink.DB, w.table, w.target, w.version, w.sink.KeepVersions)
+		if err != nil {
+			w.Abort()
+			return err
+		}
+		if snapshot != nil {
+			w.logger.Info("Предыдущая версия сохранена", "snapshot", snapshot.Name, "snapshot_version", snapshot.Version)
+		}
+		w.logger.Info("Таблица скопирована")
+		return nil
+	}
+
+	_, err := w.sink.DB.Exec("DROP TABLE " + w.table + "; ALTER TABLE " + w.target + " RENAME TO " + w.table + ";")
+	if err != nil {
+		w.Abort()
+		return fmt.Errorf("перенос данных из %s: %w", w.target, err)
+	}
+	w.logger.Info("Таблица скопирована")
+
+	return nil
+}
+
+// Abort - удаляем временную таблицу, текущие данные остаются
+func (w *postgresWriter) Abort() error {
+	if w.target == w.table {
+		return nil
+	}
+	_, err := w.sink.DB.Exec("DROP TABLE IF EXISTS " + w.target + ";")
+	return err
+}
+
+// isDataError - ошибка в данных записи (классы 22 и 23), а не в подключении или таблице
+func isDataError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	class := pqErr.Code.Class()
+	return class == "22" || class == "23"
+}
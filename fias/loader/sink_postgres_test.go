@@ -0,0 +1,132 @@
+package loader
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+	"github.com/lib/pq"
+)
+
+// badInsert - вставка, которая отклоняет пачку с записью NAME="bad" как ошибку в данных
+type badInsert struct {
+	inserted []string
+	calls    int
+}
+
+func (b *badInsert) insert(rows []Row) error {
+	b.calls++
+	for _, row := range rows {
+		if row.Values["name"] == "bad" {
+			return &pq.Error{Code: "22P02", Message: "invalid input syntax"}
+		}
+	}
+	for _, row := range rows {
+		b.inserted = append(b.inserted, row.Values["name"].(string))
+	}
+	return nil
+}
+
+// namedRows - записи с NAME из names, Offset - номер записи
+func namedRows(names ...string) []Row {
+	rows := make([]Row, len(names))
+	for i, name := range names {
+		rows[i] = Row{Values: map[string]interface{}{"name": name}, Offset: int64(i)}
+	}
+	return rows
+}
+
+func TestBisect(t *testing.T) {
+	b := &badInsert{}
+	rows := namedRows("a", "b", "bad", "c", "d", "e", "bad", "f")
+
+	rejected, err := bisect(rows, b.insert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rejected) != 2 || rejected[0].Row.Offset != 2 || rejected[1].Row.Offset != 6 {
+		t.Fatalf("отклонены %+v, ожидаются записи 2 и 6", rejected)
+	}
+	if !isDataError(rejected[0].Err) {
+		t.Errorf("ошибка %v, ожидается ошибка в данных", rejected[0].Err)
+	}
+	if got, want := strings.Join(b.inserted, ","), "a,b,c,d,e,f"; got != want {
+		t.Errorf("вставлены %s, ожидается %s", got, want)
+	}
+
+	// пачка без ошибок вставляется одним запросом
+	b = &badInsert{}
+	if rejected, err := bisect(namedRows("a", "b", "c"), b.insert); err != nil || len(rejected) != 0 || b.calls != 1 {
+		t.Errorf("отклонены %v, ошибка %v, запросов %d", rejected, err, b.calls)
+	}
+
+	// ошибка подключения не делит пачку
+	lost := &pq.Error{Code: "08006", Message: "connection failure"}
+	calls := 0
+	rejected, err = bisect(rows, func([]Row) error { calls++; return lost })
+	if !errors.Is(err, lost) || len(rejected) != 0 || calls != 1 {
+		t.Errorf("отклонены %v, ошибка %v, запросов %d", rejected, err, calls)
+	}
+}
+
+// bisectSink - приемник, который вставляет записи через bisect
+type bisectSink struct {
+	recordSink
+	badInsert
+}
+
+func (s *bisectSink) Begin(t Table) (TableWriter, error) {
+	return s, nil
+}
+
+func (s *bisectSink) Write(rows []Row) ([]Rejected, error) {
+	return bisect(rows, s.insert)
+}
+
+func TestQuarantineRejected(t *testing.T) {
+	sink := &bisectSink{}
+	l := testLoader(sink)
+	l.OnError = PolicyQuarantine
+	l.QuarantineDir = filepath.Join(t.TempDir(), "quarantine")
+
+	name := filepath.Join(t.TempDir(), "AS_ACTSTAT_20190514_b.XML")
+	data := `<?xml version="1.0" encoding="utf-8"?><ActualStatuses>` +
+		`<ActualStatus ACTSTATID="0" NAME="a" />` +
+		`<ActualStatus ACTSTATID="1" NAME="b" />` +
+		`<ActualStatus ACTSTATID="2" NAME="bad" />` +
+		`<ActualStatus ACTSTATID="3" NAME="c" />` +
+		`<ActualStatus ACTSTATID="4" NAME="d" />` +
+		`</ActualStatuses>`
+	if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := Parse[model.ActualStatus](l, name, "actstat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Rows != 4 || stats.Skipped != 1 {
+		t.Errorf("итог %+v, ожидается 4 записи и 1 пропущенная", stats)
+	}
+	if !sink.committed || sink.aborted {
+		t.Errorf("committed %v, aborted %v: остальная пачка должна быть зафиксирована", sink.committed, sink.aborted)
+	}
+	if got, want := strings.Join(sink.inserted, ","), "a,b,c,d"; got != want {
+		t.Errorf("вставлены %s, ожидается %s", got, want)
+	}
+
+	files, err := filepath.Glob(filepath.Join(l.QuarantineDir, "actstat_*.xml"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("файлы карантина %v, %v", files, err)
+	}
+	quarantined, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(quarantined), `ACTSTATID="2" NAME="bad"`) || strings.Count(string(quarantined), "<ActualStatus") != 1 {
+		t.Errorf("карантин:\n%s", quarantined)
+	}
+}
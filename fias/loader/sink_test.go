@@ -0,0 +1,139 @@
+package loader
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// recordSink - приемник, который запоминает вызовы и возвращает заданные ошибки
+type recordSink struct {
+	beginErr, commitErr error
+
+	rows      int
+	committed bool
+	aborted   bool
+}
+
+func (s *recordSink) Begin(t Table) (TableWriter, error) {
+	if s.beginErr != nil {
+		return nil, s.beginErr
+	}
+	return s, nil
+}
+
+func (s *recordSink) Write(rows []Row) ([]Rejected, error) {
+	s.rows += len(rows)
+	return nil, nil
+}
+
+func (s *recordSink) Commit() error {
+	if s.commitErr != nil {
+		return s.commitErr
+	}
+	s.committed = true
+	return nil
+}
+
+func (s *recordSink) Abort() error {
+	s.aborted = true
+	return nil
+}
+
+// testLoader - загрузчик в sink без вывода в лог
+func testLoader(sink Sink) *Loader {
+	l := New(nil)
+	l.Sink = sink
+	l.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return l
+}
+
+// writeXML - файл AS_ACTSTAT с count записями
+func writeXML(t *testing.T, count int) string {
+	t.Helper()
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="utf-8"?><ActualStatuses>`)
+	for i := 0; i < count; i++ {
+		b.WriteString(`<ActualStatus ACTSTATID="1" NAME="Актуальный" />`)
+	}
+	b.WriteString(`</ActualStatuses>`)
+	name := filepath.Join(t.TempDir(), "AS_ACTSTAT_20190514_b.XML")
+	if err := os.WriteFile(name, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+func TestMultiSinkCommitFails(t *testing.T) {
+	failed := errors.New("нет места на диске")
+	first, second, third := &recordSink{}, &recordSink{commitErr: failed}, &recordSink{}
+
+	_, err := Parse[model.ActualStatus](testLoader(MultiSink{first, second, third}), writeXML(t, 3), "actstat")
+	if !errors.Is(err, failed) {
+		t.Fatalf("ошибка %v, ожидается %v", err, failed)
+	}
+
+	for i, s := range []*recordSink{first, second, third} {
+		if s.rows != 3 {
+			t.Errorf("приемник %d: записано %d, ожидается 3", i, s.rows)
+		}
+	}
+	if !first.committed || first.aborted {
+		t.Errorf("первый приемник уже зафиксирован и не отменяется: %+v", first)
+	}
+	if !second.aborted || !third.aborted || third.committed {
+		t.Errorf("приемники после ошибки должны быть отменены: %+v, %+v", second, third)
+	}
+}
+
+func TestMultiSinkBeginFails(t *testing.T) {
+	failed := errors.New("нет подключения")
+	first, second := &recordSink{}, &recordSink{beginErr: failed}
+
+	if _, err := (MultiSink{first, second}).Begin(Table{Name: "actstat"}); !errors.Is(err, failed) {
+		t.Fatalf("ошибка %v, ожидается %v", err, failed)
+	}
+	if !first.aborted {
+		t.Error("начатый приемник не отменен")
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "actstat.jsonl")
+	if err := os.WriteFile(path, []byte("прежняя выгрузка\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// приемник перед файлом не зафиксирован: прежняя выгрузка остается, временный файл удален
+	failed := errors.New("ошибка проверки")
+	_, err := Parse[model.ActualStatus](testLoader(MultiSink{&recordSink{commitErr: failed}, &FileSink{Dir: dir}}), writeXML(t, 2), "actstat")
+	if !errors.Is(err, failed) {
+		t.Fatalf("ошибка %v, ожидается %v", err, failed)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "прежняя выгрузка\n" {
+		t.Errorf("выгрузка изменена: %q", data)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("временный файл не удален: %v", err)
+	}
+
+	// успешная загрузка заменяет выгрузку
+	if _, err := Parse[model.ActualStatus](testLoader(&FileSink{Dir: dir}), writeXML(t, 2), "actstat"); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"actstatid":1,"name":"Актуальный"}` + "\n"
+	if string(data) != want+want {
+		t.Errorf("выгрузка %q, ожидается %q", data, want+want)
+	}
+}
@@ -0,0 +1,291 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Snapshot - сохраненная предыдущая версия таблицы
+type Snapshot struct {
+	Table     string
+	Version   int
+	Name      string // таблица снимка <таблица>_v<версия>
+	CreatedAt time.Time
+}
+
+// SnapshotName - имя таблицы снимка версии version
+func SnapshotName(table string, version int) string {
+	return fmt.Sprintf("%s_v%d", table, version)
+}
+
+// ensureSnapshotTables - таблицы учета версий: table_version (версия текущих данных) и snapshots
+func ensureSnapshotTables(q interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}) error {
+	_, err := q.Exec(`CREATE TABLE IF NOT EXISTS table_version (
+	table_name text PRIMARY KEY,
+	version_id integer NOT NULL
+);
+CREATE TABLE IF NOT EXISTS snapshots (
+	table_name text NOT NULL,
+	version_id integer NOT NULL,
+	snapshot_name text NOT NULL,
+	created_at timestamp with time zone NOT NULL DEFAULT now(),
+	PRIMARY KEY (table_name, version_id)
+);`)
+	if err != nil {
+		return fmt.Errorf("создание таблиц учета версий: %w", err)
+	}
+	return nil
+}
+
+// tableVersion - версия данных в таблице table, 0 если неизвестна
+func tableVersion(tx *sql.Tx, table string) (int, error) {
+	var version int
+	err := tx.QueryRow("SELECT version_id FROM table_version WHERE table_name = $1;", table).Scan(&version)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return version, err
+}
+
+// setTableVersion - запоминаем версию данных таблицы
+func setTableVersion(tx *sql.Tx, table string, version int) error {
+	_, err := tx.Exec(`INSERT INTO table_version (table_name, version_id) VALUES ($1, $2)
+ON CONFLICT (table_name) DO UPDATE SET version_id = EXCLUDED.version_id;`, table, version)
+	return err
+}
+
+// swapKeeping - подменяем table таблицей staging версии version, текущие данные сохраняем
+// снимком <table>_v<версия> и оставляем не больше keep снимков
+func swapKeeping(db *sql.DB, table, staging string, version, keep int) (*Snapshot, error) {
+	if err := ensureSnapshotTables(db); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := tableVersion(tx, table)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot *Snapshot
+	if current != 0 && current != version {
+		snapshot = &Snapshot{Table: table, Version: current, Name: SnapshotName(table, current), CreatedAt: time.Now()}
+		_, err = tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %[2]s; ALTER TABLE %[1]s RENAME TO %[2]s;", table, snapshot.Name))
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO snapshots (table_name, version_id, snapshot_name, created_at) VALUES ($1, $2, $3, $4)
+ON CONFLICT (table_name, version_id) DO UPDATE SET snapshot_name = EXCLUDED.snapshot_name, created_at = EXCLUDED.created_at;`,
+				table, current, snapshot.Name, snapshot.CreatedAt)
+		}
+	} else {
+		_, err = tx.Exec("DROP TABLE " + table + ";")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("сохранение снимка %s: %w", table, err)
+	}
+
+	if _, err := tx.Exec("ALTER TABLE " + staging + " RENAME TO " + table + ";"); err != nil {
+		return nil, fmt.Errorf("перенос данных из %s: %w", staging, err)
+	}
+	if err := setTableVersion(tx, table, version); err != nil {
+		return nil, err
+	}
+	if err := pruneSnapshots(tx, table, keep); err != nil {
+		return nil, err
+	}
+
+	return snapshot, tx.Commit()
+}
+
+// pruneSnapshots - удаляем снимки table старше keep последних
+func pruneSnapshots(tx *sql.Tx, table string, keep int) error {
+	rows, err := tx.Query(`SELECT snapshot_name FROM snapshots WHERE table_name = $1
+ORDER BY version_id DESC OFFSET $2;`, table, keep)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return err
+		}
+		names = append(names, name)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + name + ";"); err != nil {
+			return fmt.Errorf("удаление снимка %s: %w", name, err)
+		}
+		if _, err := tx.Exec("DELETE FROM snapshots WHERE snapshot_name = $1;", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListSnapshots - сохраненные снимки всех таблиц, новые первыми
+func ListSnapshots(db *sql.DB) ([]Snapshot, error) {
+	if err := ensureSnapshotTables(db); err != nil {
+		return nil, err
+	}
+
+	rows, err := db.Query("SELECT table_name, version_id, snapshot_name, created_at FROM snapshots ORDER BY version_id DESC, table_name;")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var snapshots []Snapshot
+	for rows.Next() {
+		var s Snapshot
+		if err := rows.Scan(&s.Table, &s.Version, &s.Name, &s.CreatedAt); err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, s)
+	}
+	return snapshots, rows.Err()
+}
+
+// Rollback - возвращаем таблицы к снимкам версии version в одной транзакции;
+// текущие данные каждой таблицы сохраняются снимком своей версии, откат можно отменить
+func Rollback(db *sql.DB, version int) (tables []string, err error) {
+	if err := ensureSnapshotTables(db); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query("SELECT table_name, snapshot_name FROM snapshots WHERE version_id = $1 ORDER BY table_name;", version)
+	if err != nil {
+		return nil, err
+	}
+	snapshots := map[string]string{}
+	for rows.Next() {
+		var table, name string
+		if err := rows.Scan(&table, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		snapshots[table] = name
+		tables = append(tables, table)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("нет снимков версии %d", version)
+	}
+
+	for _, table := range tables {
+		if err := restoreSnapshot(tx, table, snapshots[table], version); err != nil {
+			return nil, err
+		}
+	}
+
+	return tables, tx.Commit()
+}
+
+// restoreSnapshot - подменяем table снимком name версии version, текущие данные
+// сохраняем снимком своей версии
+func restoreSnapshot(tx *sql.Tx, table, name string, version int) error {
+	current, err := tableVersion(tx, table)
+	if err != nil {
+		return err
+	}
+
+	if current != 0 {
+		keep := SnapshotName(table, current)
+		_, err = tx.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %[2]s; ALTER TABLE %[1]s RENAME TO %[2]s;", table, keep))
+		if err == nil {
+			_, err = tx.Exec(`INSERT INTO snapshots (table_name, version_id, snapshot_name) VALUES ($1, $2, $3)
+ON CONFLICT (table_name, version_id) DO UPDATE SET snapshot_name = EXCLUDED.snapshot_name, created_at = now();`, table, current, keep)
+		}
+	} else {
+		_, err = tx.Exec("DROP TABLE " + table + ";")
+	}
+	if err != nil {
+		return fmt.Errorf("сохранение текущих данных %s: %w", table, err)
+	}
+
+	if _, err := tx.Exec("ALTER TABLE " + name + " RENAME TO " + table + ";"); err != nil {
+		return fmt.Errorf("восстановление %s: %w", table, err)
+	}
+	if _, err := tx.Exec("DELETE FROM snapshots WHERE table_name = $1 AND version_id = $2;", table, version); err != nil {
+		return err
+	}
+	return setTableVersion(tx, table, version)
+}
+
+// Undo - отменяем загрузку версии version: таблицы этой версии возвращаются к последнему
+// снимку более ранней версии, загруженные данные остаются снимком <таблица>_v<version>;
+// skipped - таблицы версии без снимка, их данные не меняются
+func Undo(db *sql.DB, version int) (tables, skipped []string, err error) {
+	if err := ensureSnapshotTables(db); err != nil {
+		return nil, nil, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.Query(`SELECT v.table_name, s.snapshot_name, s.version_id FROM table_version v
+LEFT JOIN LATERAL (SELECT snapshot_name, version_id FROM snapshots
+	WHERE table_name = v.table_name AND version_id < v.version_id
+	ORDER BY version_id DESC LIMIT 1) s ON true
+WHERE v.version_id = $1 ORDER BY v.table_name;`, version)
+	if err != nil {
+		return nil, nil, err
+	}
+	type restore struct {
+		table, name string
+		version     int
+	}
+	var restores []restore
+	for rows.Next() {
+		var table string
+		var name sql.NullString
+		var previous sql.NullInt64
+		if err := rows.Scan(&table, &name, &previous); err != nil {
+			rows.Close()
+			return nil, nil, err
+		}
+		if !name.Valid {
+			skipped = append(skipped, table)
+			continue
+		}
+		restores = append(restores, restore{table, name.String, int(previous.Int64)})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	for _, r := range restores {
+		if err := restoreSnapshot(tx, r.table, r.name, r.version); err != nil {
+			return nil, nil, err
+		}
+		tables = append(tables, r.table)
+	}
+
+	return tables, skipped, tx.Commit()
+}
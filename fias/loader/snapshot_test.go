@@ -0,0 +1,151 @@
+package loader
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// expectExec - ожидаем запрос query (без регулярных выражений) с аргументами args
+func expectExec(mock sqlmock.Sqlmock, query string, args ...driver.Value) {
+	e := mock.ExpectExec(regexp.QuoteMeta(query))
+	if len(args) > 0 {
+		e = e.WithArgs(args...)
+	}
+	e.WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+// expectVersion - версия данных таблицы в table_version, 0 - нет записи
+func expectVersion(mock sqlmock.Sqlmock, table string, version int) {
+	rows := sqlmock.NewRows([]string{"version_id"})
+	if version != 0 {
+		rows.AddRow(version)
+	}
+	mock.ExpectQuery(`SELECT version_id FROM table_version`).WithArgs(table).WillReturnRows(rows)
+}
+
+// expectRestore - запросы restoreSnapshot: текущая версия current сохраняется снимком,
+// снимок name версии version становится таблицей
+func expectRestore(mock sqlmock.Sqlmock, table, name string, current, version int) {
+	expectVersion(mock, table, current)
+	keep := SnapshotName(table, current)
+	expectExec(mock, "DROP TABLE IF EXISTS "+keep+"; ALTER TABLE "+table+" RENAME TO "+keep+";")
+	expectExec(mock, "INSERT INTO snapshots", table, current, keep)
+	expectExec(mock, "ALTER TABLE "+name+" RENAME TO "+table+";")
+	expectExec(mock, "DELETE FROM snapshots WHERE table_name = $1 AND version_id = $2;", table, version)
+	expectExec(mock, "INSERT INTO table_version", table, version)
+}
+
+func TestSnapshots(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	// загрузка 634: данные 633 уходят в снимок, снимок 632 удаляется при keep = 1
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectBegin()
+	expectVersion(mock, "house", 633)
+	expectExec(mock, "DROP TABLE IF EXISTS house_v633; ALTER TABLE house RENAME TO house_v633;")
+	expectExec(mock, "INSERT INTO snapshots", "house", 633, "house_v633", sqlmock.AnyArg())
+	expectExec(mock, "ALTER TABLE house_staging RENAME TO house;")
+	expectExec(mock, "INSERT INTO table_version", "house", 634)
+	mock.ExpectQuery(`SELECT snapshot_name FROM snapshots WHERE table_name = \$1\s+ORDER BY version_id DESC OFFSET \$2`).
+		WithArgs("house", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"snapshot_name"}).AddRow("house_v632"))
+	expectExec(mock, "DROP TABLE IF EXISTS house_v632;")
+	expectExec(mock, "DELETE FROM snapshots WHERE snapshot_name = $1;", "house_v632")
+	mock.ExpectCommit()
+
+	snapshot, err := swapKeeping(db, "house", "house_staging", 634, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot == nil || snapshot.Name != "house_v633" || snapshot.Version != 633 {
+		t.Errorf("снимок %+v, ожидается house_v633", snapshot)
+	}
+
+	// первая загрузка таблицы: снимка нет
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectBegin()
+	expectVersion(mock, "rooms", 0)
+	expectExec(mock, "DROP TABLE rooms;")
+	expectExec(mock, "ALTER TABLE rooms_staging RENAME TO rooms;")
+	expectExec(mock, "INSERT INTO table_version", "rooms", 634)
+	mock.ExpectQuery(`SELECT snapshot_name FROM snapshots`).WithArgs("rooms", 1).WillReturnRows(sqlmock.NewRows([]string{"snapshot_name"}))
+	mock.ExpectCommit()
+
+	if snapshot, err := swapKeeping(db, "rooms", "rooms_staging", 634, 1); err != nil || snapshot != nil {
+		t.Errorf("снимок %+v, ошибка %v, ожидается без снимка", snapshot, err)
+	}
+
+	// список снимков
+	created := time.Date(2019, 5, 14, 0, 0, 0, 0, time.UTC)
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectQuery(`SELECT table_name, version_id, snapshot_name, created_at FROM snapshots ORDER BY version_id DESC`).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "version_id", "snapshot_name", "created_at"}).
+			AddRow("house", 633, "house_v633", created))
+
+	snapshots, err := ListSnapshots(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Snapshot{{Table: "house", Version: 633, Name: "house_v633", CreatedAt: created}}; !slices.Equal(snapshots, want) {
+		t.Errorf("снимки %+v, ожидается %+v", snapshots, want)
+	}
+
+	// откат к 633: данные 634 сохраняются снимком, откат можно отменить
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT table_name, snapshot_name FROM snapshots WHERE version_id = \$1`).
+		WithArgs(633).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "snapshot_name"}).AddRow("house", "house_v633"))
+	expectRestore(mock, "house", "house_v633", 634, 633)
+	mock.ExpectCommit()
+
+	tables, err := Rollback(db, 633)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tables, []string{"house"}) {
+		t.Errorf("откачены таблицы %v, ожидается [house]", tables)
+	}
+
+	// откат к версии без снимков
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`FROM snapshots WHERE version_id = \$1`).WithArgs(600).WillReturnRows(sqlmock.NewRows([]string{"table_name", "snapshot_name"}))
+	mock.ExpectRollback()
+
+	if _, err := Rollback(db, 600); err == nil {
+		t.Error("откат к версии без снимков без ошибки")
+	}
+
+	// отмена 633: address_objects возвращается к снимку 632, у rooms снимка нет
+	expectExec(mock, "CREATE TABLE IF NOT EXISTS table_version")
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT v.table_name, s.snapshot_name, s.version_id FROM table_version v`).
+		WithArgs(633).
+		WillReturnRows(sqlmock.NewRows([]string{"table_name", "snapshot_name", "version_id"}).
+			AddRow("address_objects", "address_objects_v632", 632).
+			AddRow("rooms", nil, nil))
+	expectRestore(mock, "address_objects", "address_objects_v632", 633, 632)
+	mock.ExpectCommit()
+
+	tables, skipped, err := Undo(db, 633)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tables, []string{"address_objects"}) || !slices.Equal(skipped, []string{"rooms"}) {
+		t.Errorf("отменены %v, без снимка %v, ожидается [address_objects] и [rooms]", tables, skipped)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,148 @@
+package loader
+
+import (
+	"database/sql"
+	"fmt"
+	"log/slog"
+)
+
+// IntegrityCheck - проверка целостности, Query возвращает ключи нарушений в колонке key
+type IntegrityCheck struct {
+	Name  string
+	Table string
+	Query string
+}
+
+// ValidationResult - результат проверки целостности
+type ValidationResult struct {
+	IntegrityCheck
+	Count  int64
+	Sample []string
+	Err    error
+}
+
+// orphanCheck - ссылка column таблицы table на несуществующий refColumn в refTable
+func orphanCheck(name, table, key, column, refTable, refColumn string) IntegrityCheck {
+	return IntegrityCheck{
+		Name:  name,
+		Table: table,
+		Query: fmt.Sprintf(`SELECT t.%[2]s AS key FROM %[1]s t
+WHERE coalesce(t.%[3]s, '') <> '' AND NOT EXISTS (SELECT 1 FROM %[4]s r WHERE r.%[5]s = t.%[3]s)`,
+			table, key, column, refTable, refColumn),
+	}
+}
+
+// statusCheck - код статуса column, отсутствующий в справочнике refTable
+func statusCheck(name, table, key, column, refTable, refColumn string) IntegrityCheck {
+	return IntegrityCheck{
+		Name:  name,
+		Table: table,
+		Query: fmt.Sprintf(`SELECT t.%[2]s || ': ' || t.%[3]s AS key FROM %[1]s t
+WHERE NOT EXISTS (SELECT 1 FROM %[4]s r WHERE r.%[5]s = t.%[3]s)`,
+			table, key, column, refTable, refColumn),
+	}
+}
+
+// duplicateCheck - несколько актуальных записей с одним GUID
+func duplicateCheck(name, table, guid, actual string) IntegrityCheck {
+	return IntegrityCheck{
+		Name:  name,
+		Table: table,
+		Query: fmt.Sprintf(`SELECT %[2]s AS key FROM %[1]s WHERE %[3]s GROUP BY %[2]s HAVING count(*) > 1`,
+			table, guid, actual),
+	}
+}
+
+// IntegrityChecks - проверки после загрузки
+var IntegrityChecks = []IntegrityCheck{
+	orphanCheck("address_objects.parentguid", "address_objects", "aoid", "parentguid", "address_objects", "aoguid"),
+	{
+		Name:  "address_objects.region",
+		Table: "address_objects",
+		Query: `SELECT aoid AS key FROM address_objects WHERE coalesce(parentguid, '') = '' AND aolevel <> 1`,
+	},
+	orphanCheck("address_objects.previd", "address_objects", "aoid", "previd", "address_objects", "aoid"),
+	orphanCheck("address_objects.nextid", "address_objects", "aoid", "nextid", "address_objects", "aoid"),
+	orphanCheck("house.aoguid", "house", "houseid", "aoguid", "address_objects", "aoguid"),
+	orphanCheck("house_interval.aoguid", "house_interval", "houseintid", "aoguid", "address_objects", "aoguid"),
+	orphanCheck("landmark.aoguid", "landmark", "landid", "aoguid", "address_objects", "aoguid"),
+	orphanCheck("rooms.houseguid", "rooms", "roomid", "houseguid", "house", "houseguid"),
+	orphanCheck("rooms.previd", "rooms", "roomid", "previd", "rooms", "roomid"),
+	orphanCheck("rooms.nextid", "rooms", "roomid", "nextid", "rooms", "roomid"),
+	orphanCheck("steads.parentguid", "steads", "steadid", "parentguid", "address_objects", "aoguid"),
+	orphanCheck("steads.previd", "steads", "steadid", "previd", "steads", "steadid"),
+	orphanCheck("steads.nextid", "steads", "steadid", "nextid", "steads", "steadid"),
+
+	duplicateCheck("address_objects.actual", "address_objects", "aoguid", "actstatus = 1"),
+	duplicateCheck("house.actual", "house", "houseguid", "enddate::date > current_date"),
+	duplicateCheck("rooms.actual", "rooms", "roomguid", "enddate::date > current_date AND coalesce(nextid, '') = ''"),
+	duplicateCheck("steads.actual", "steads", "steadguid", "enddate::date > current_date AND coalesce(nextid, '') = ''"),
+
+	statusCheck("address_objects.actstatus", "address_objects", "aoid", "actstatus", "actual_status", "actstatid"),
+	statusCheck("address_objects.centstatus", "address_objects", "aoid", "centstatus", "center_status", "centerstid"),
+	statusCheck("address_objects.currstatus", "address_objects", "aoid", "currstatus", "current_status", "curentstid"),
+	statusCheck("address_objects.operstatus", "address_objects", "aoid", "operstatus", "operation_status", "operstatid"),
+	statusCheck("house.eststatus", "house", "houseid", "eststatus", "estate_status", "eststatid"),
+	statusCheck("house.strstatus", "house", "houseid", "strstatus", "structure_status", "strstatid"),
+	statusCheck("house.statstatus", "house", "houseid", "statstatus", "house_state_status", "housestid"),
+	statusCheck("house_interval.intstatus", "house_interval", "houseintid", "intstatus", "interval_status", "intvstatid"),
+	statusCheck("normative_document.doctype", "normative_document", "normdocid", "doctype", "normative_document_type", "ndtypeid"),
+	statusCheck("rooms.operstatus", "rooms", "roomid", "operstatus", "operation_status", "operstatid"),
+	statusCheck("steads.operstatus", "steads", "steadid", "operstatus", "operation_status", "operstatid"),
+}
+
+// ValidateIntegrity - проверяем ссылочную целостность загруженных таблиц, sample - сколько ключей нарушений вернуть
+func ValidateIntegrity(db *sql.DB, sample int) []ValidationResult {
+	results := make([]ValidationResult, 0, len(IntegrityChecks))
+	for _, check := range IntegrityChecks {
+		result := ValidationResult{IntegrityCheck: check}
+		result.Count, result.Sample, result.Err = runCheck(db, check, sample)
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// runCheck - число нарушений и первые sample ключей одним запросом: count(*) OVER ()
+// считается по всем нарушениям до LIMIT
+func runCheck(pgDb *sql.DB, check IntegrityCheck, sample int) (int64, []string, error) {
+	rows, err := pgDb.Query(fmt.Sprintf("SELECT key::text, count(*) OVER () FROM (%s) t LIMIT %d;", check.Query, max(sample, 1)))
+	if err != nil {
+		return 0, nil, err
+	}
+	defer rows.Close()
+
+	var count int64
+	var keys []string
+	for rows.Next() {
+		var key sql.NullString
+		if err := rows.Scan(&key, &count); err != nil {
+			return count, keys, err
+		}
+		if len(keys) < sample {
+			keys = append(keys, key.String)
+		}
+	}
+
+	return count, keys, rows.Err()
+}
+
+// ReportValidation - выводим отчет, возвращаем общее число нарушений и кол-во проверок,
+// которые не удалось выполнить (например, нет таблицы)
+func ReportValidation(logger *slog.Logger, results []ValidationResult) (total int64, failed int) {
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			logger.Warn("Проверка не выполнена", "check", r.Name, "table", r.Table, "err", r.Err)
+		case r.Count > 0:
+			total += r.Count
+			logger.Warn("Нарушение целостности", "check", r.Name, "table", r.Table, "rows", r.Count, "sample", r.Sample)
+		default:
+			logger.Debug("Проверка пройдена", "check", r.Name, "table", r.Table)
+		}
+	}
+
+	logger.Info("Проверка целостности закончена", "checks", len(results), "violations", total, "not_run", failed)
+	return total, failed
+}
@@ -0,0 +1,75 @@
+package loader
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestReportValidation(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name    string
+		results []ValidationResult
+		total   int64
+		failed  int
+	}{
+		{"пусто", nil, 0, 0},
+		{"без нарушений", []ValidationResult{{}, {}}, 0, 0},
+		{"нарушения", []ValidationResult{{Count: 3}, {Count: 2}, {}}, 5, 0},
+		{"нет таблицы", []ValidationResult{{Err: errors.New(`relation "house" does not exist`)}}, 0, 1},
+		{"вместе", []ValidationResult{{Count: 1}, {Err: errors.New("timeout")}}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, failed := ReportValidation(logger, tt.results)
+			if total != tt.total || failed != tt.failed {
+				t.Errorf("ReportValidation = %d, %d; ожидается %d, %d", total, failed, tt.total, tt.failed)
+			}
+		})
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	check := IntegrityCheck{Name: "house.aoguid", Table: "house", Query: "SELECT houseid AS key FROM house"}
+	query := regexp.QuoteMeta("SELECT key::text, count(*) OVER () FROM (SELECT houseid AS key FROM house) t LIMIT 2;")
+	mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"key", "count"}).AddRow("a", 5).AddRow("b", 5))
+	mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"key", "count"}))
+	// sample = 0: ключи не нужны, но число нарушений приходит с первой строкой
+	mock.ExpectQuery(regexp.QuoteMeta("LIMIT 1;")).WillReturnRows(sqlmock.NewRows([]string{"key", "count"}).AddRow("a", 5))
+
+	tests := []struct {
+		sample int
+		count  int64
+		keys   []string
+	}{
+		{2, 5, []string{"a", "b"}},
+		{2, 0, nil},
+		{0, 5, nil},
+	}
+	for _, tt := range tests {
+		count, keys, err := runCheck(db, check, tt.sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != tt.count || !slices.Equal(keys, tt.keys) {
+			t.Errorf("runCheck(sample %d) = %d, %v; ожидается %d, %v", tt.sample, count, keys, tt.count, tt.keys)
+		}
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
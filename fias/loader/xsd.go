@@ -0,0 +1,152 @@
+package loader
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// identPattern - допустимое имя таблицы или колонки из схемы: имена попадают в DDL
+var identPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// prefixPattern - префикс файла ФИАС до даты или версии схемы: AS_ADDROBJ_20190101_... и AS_ADDROBJ_2_250_...
+var prefixPattern = regexp.MustCompile(`(?i)^(AS_[A-Z_]+?)_\d`)
+
+// Schema - описание файла из XSD схемы, поставляемой в архиве
+type Schema struct {
+	File       string
+	Prefix     string // AS_FLATTYPE
+	Root       string // корневой элемент: FlatTypes
+	Element    string // элемент записи: FlatType
+	Attributes []SchemaAttribute
+}
+
+// SchemaAttribute - атрибут записи
+type SchemaAttribute struct {
+	Name     string
+	Type     string // тип XSD без префикса: string, integer, date...
+	Required bool
+}
+
+type xsdSchema struct {
+	Elements []xsdElement `xml:"element"`
+}
+
+type xsdElement struct {
+	Name        string      `xml:"name,attr"`
+	ComplexType *xsdComplex `xml:"complexType"`
+}
+
+type xsdComplex struct {
+	Sequence   []xsdElement   `xml:"sequence>element"`
+	Attributes []xsdAttribute `xml:"attribute"`
+}
+
+type xsdAttribute struct {
+	Name       string `xml:"name,attr"`
+	Type       string `xml:"type,attr"`
+	Use        string `xml:"use,attr"`
+	SimpleType *struct {
+		Restriction struct {
+			Base string `xml:"base,attr"`
+		} `xml:"restriction"`
+	} `xml:"simpleType"`
+}
+
+// FilePrefix - префикс имени файла ФИАС (AS_HOUSE), пустая строка для посторонних файлов
+func FilePrefix(name string) string {
+	m := prefixPattern.FindStringSubmatch(name)
+	if m == nil {
+		return ""
+	}
+	return strings.ToUpper(m[1])
+}
+
+// ParseSchema - разбираем XSD схему файла ФИАС: корень - список, в нем элемент записи с атрибутами
+func ParseSchema(path string) (*Schema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc xsdSchema
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	if len(doc.Elements) != 1 || doc.Elements[0].ComplexType == nil || len(doc.Elements[0].ComplexType.Sequence) != 1 {
+		return nil, fmt.Errorf("%s: ожидается корневой элемент со списком записей", filepath.Base(path))
+	}
+
+	root := doc.Elements[0]
+	record := root.ComplexType.Sequence[0]
+	if record.ComplexType == nil {
+		return nil, fmt.Errorf("%s: у элемента %s нет атрибутов", filepath.Base(path), record.Name)
+	}
+
+	s := &Schema{File: path, Prefix: FilePrefix(filepath.Base(path)), Root: root.Name, Element: record.Name}
+	if s.Prefix != "" && !identPattern.MatchString(s.TableName()) {
+		return nil, fmt.Errorf("%s: недопустимое имя таблицы %q", filepath.Base(path), s.TableName())
+	}
+	for _, a := range record.ComplexType.Attributes {
+		if !identPattern.MatchString(a.Name) {
+			return nil, fmt.Errorf("%s: недопустимое имя атрибута %q", filepath.Base(path), a.Name)
+		}
+		typ := a.Type
+		if typ == "" && a.SimpleType != nil {
+			typ = a.SimpleType.Restriction.Base
+		}
+		if i := strings.IndexByte(typ, ':'); i >= 0 {
+			typ = typ[i+1:]
+		}
+		s.Attributes = append(s.Attributes, SchemaAttribute{Name: a.Name, Type: typ, Required: a.Use == "required"})
+	}
+	return s, nil
+}
+
+// LoadSchemas - XSD схемы в каталоге dir и его подкаталогах по префиксу файла;
+// схемы, которые не удалось разобрать, пропускаются с предупреждением в logger
+func LoadSchemas(dir string, logger *slog.Logger) (map[string]*Schema, error) {
+	schemas := map[string]*Schema{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".xsd") {
+			return err
+		}
+		s, err := ParseSchema(path)
+		if err != nil {
+			logger.Warn("XSD схема пропущена", "schema", filepath.Base(path), "err", err)
+			return nil
+		}
+		if s.Prefix != "" {
+			schemas[s.Prefix] = s
+		}
+		return nil
+	})
+	return schemas, err
+}
+
+// TableName - таблица для файла без описания в Registry: AS_FLATTYPE -> flattype
+func (s *Schema) TableName() string {
+	return strings.ToLower(strings.TrimPrefix(s.Prefix, "AS_"))
+}
+
+// ColumnType - тип колонки PostgreSQL для атрибута
+func (a SchemaAttribute) ColumnType() string {
+	switch a.Type {
+	case "integer", "int", "long", "short", "byte", "unsignedInt", "unsignedShort", "unsignedByte", "nonNegativeInteger", "positiveInteger":
+		return "bigint"
+	case "date":
+		return "date"
+	case "dateTime":
+		return "timestamp"
+	case "boolean":
+		return "boolean"
+	case "decimal", "double", "float":
+		return "numeric"
+	}
+	return "text"
+}
@@ -0,0 +1,111 @@
+package loader
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// TestColumnKeys - атрибуты XML записей совпадают с колонками Row() после columnKey,
+// иначе checkSchema предупреждал бы о них при каждой загрузке
+func TestColumnKeys(t *testing.T) {
+	records := []model.Record{
+		model.ActualStatus{}, model.Object{}, model.CenterStatus{}, model.CurrentStatus{},
+		model.House{}, model.HouseInterval{}, model.NormativeDocument{}, model.EstateStatus{},
+		model.FlatType{}, model.HouseStateStatus{}, model.IntervalStatus{}, model.Landmark{},
+		model.NormativeDocumentType{}, model.OperationStatus{}, model.AddressObjectType{},
+		model.StructureStatus{}, model.Stead{}, model.Room{}, model.RoomType{},
+	}
+
+	for _, record := range records {
+		columns := map[string]bool{}
+		for column := range record.Row() {
+			columns[columnKey(column)] = true
+		}
+
+		typ := reflect.TypeOf(record)
+		for i := 0; i < typ.NumField(); i++ {
+			name, opts, _ := strings.Cut(typ.Field(i).Tag.Get("xml"), ",")
+			if opts != "attr" {
+				continue
+			}
+			if !columns[columnKey(name)] {
+				t.Errorf("%s: атрибут %s не найден в колонках", record.Element(), name)
+			}
+		}
+	}
+}
+
+const flatTypeXSD = `<?xml version="1.0" encoding="utf-8"?>
+<xs:schema xmlns:xs="http://www.w3.org/2001/XMLSchema">
+  <xs:element name="FlatTypes">
+    <xs:complexType>
+      <xs:sequence>
+        <xs:element name="FlatType">
+          <xs:complexType>
+            <xs:attribute name="FLTYPEID" type="xs:integer" use="required"/>
+            <xs:attribute name="%s" use="required">
+              <xs:simpleType><xs:restriction base="xs:string"/></xs:simpleType>
+            </xs:attribute>
+          </xs:complexType>
+        </xs:element>
+      </xs:sequence>
+    </xs:complexType>
+  </xs:element>
+</xs:schema>`
+
+func TestLoadSchemas(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"AS_FLATTYPE_2_250_08_04_01_01.xsd": strings.Replace(flatTypeXSD, "%s", "NAME", 1),
+		"AS_ROOMTYPE_2_250_08_04_01_01.xsd": "<xs:schema",
+		"AS_HOUSE_2_250_08_04_01_01.xsd":    strings.Replace(flatTypeXSD, "%s", `NAME"); DROP TABLE house; --`, 1),
+		"AS_STEAD_2_250_08_04_01_01.xsd":    "<schema/>",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	schemas, err := LoadSchemas(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schemas) != 1 {
+		t.Fatalf("загружено схем %d, ожидается 1: %v", len(schemas), schemas)
+	}
+
+	s := schemas["AS_FLATTYPE"]
+	if s == nil {
+		t.Fatal("нет схемы AS_FLATTYPE")
+	}
+	if s.TableName() != "flattype" || s.Element != "FlatType" || len(s.Attributes) != 2 {
+		t.Errorf("схема %+v", s)
+	}
+	if a := s.Attributes[1]; a.Name != "NAME" || a.Type != "string" || !a.Required || a.ColumnType() != "text" {
+		t.Errorf("атрибут %+v", a)
+	}
+}
+
+func TestFilePrefix(t *testing.T) {
+	tests := map[string]string{
+		"AS_ADDROBJ_20190101_0b7f3a6c.XML":  "AS_ADDROBJ",
+		"as_flattype_2_250_08_04_01_01.xsd": "AS_FLATTYPE",
+		"AS_NORMDOC_TYPE_20190101_abcd.XML": "AS_NORMDOC_TYPE",
+		"readme.txt":                        "",
+		"AS_HOUSE; DROP TABLE house_1.XML":  "",
+		"AS_HOUSE_ABC.XML":                  "",
+	}
+	for name, want := range tests {
+		if got := FilePrefix(name); got != want {
+			t.Errorf("FilePrefix(%q) = %q, ожидается %q", name, got, want)
+		}
+	}
+}
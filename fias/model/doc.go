@@ -0,0 +1,2 @@
+// Package model - записи справочников и классификаторов ФИАС (XML выгрузка)
+package model
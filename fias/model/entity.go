@@ -0,0 +1,42 @@
+package model
+
+// Entity - запись адресного реестра с глобальным идентификатором (GUID)
+type Entity interface {
+	Record
+	GUID() string
+}
+
+// Operational - запись с кодом операции OPERSTATUS (справочник OperationStatus)
+type Operational interface {
+	Operation() int
+}
+
+// GUID - глобальный идентификатор адресного объекта
+func (o Object) GUID() string { return o.AOGUID }
+
+// Operation - статус действия над записью (OPERSTATUS)
+func (o Object) Operation() int { return o.OPERSTATUS }
+
+// GUID - глобальный идентификатор дома
+func (h House) GUID() string { return h.HouseGUID }
+
+// GUID - глобальный идентификатор интервала домов
+func (h HouseInterval) GUID() string { return h.INTGUID }
+
+// GUID - глобальный идентификатор ориентира
+func (l Landmark) GUID() string { return l.LANDGUID }
+
+// GUID - идентификатор нормативного документа
+func (n NormativeDocument) GUID() string { return n.NORMDOCID }
+
+// GUID - глобальный идентификатор помещения
+func (r Room) GUID() string { return r.RoomGuid }
+
+// Operation - статус действия над записью (OPERSTATUS)
+func (r Room) Operation() int { return r.OperStatus }
+
+// GUID - глобальный идентификатор земельного участка
+func (s Stead) GUID() string { return s.STEADGUID }
+
+// Operation - статус действия над записью (OPERSTATUS)
+func (s Stead) Operation() int { return s.OPERSTATUS }
@@ -0,0 +1,276 @@
+package model
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"unicode"
+)
+
+// HouseLabel - номер дома из частей House: признак владения ESTSTATUS и номер,
+// корпус, признак строения STRSTATUS и номер строения
+type HouseLabel struct {
+	EstStatus int
+	HouseNum  string
+	BuildNum  string
+	StrStatus int
+	StrucNum  string
+}
+
+// HouseKind - сокращение и полное наименование признака
+type HouseKind struct {
+	Short, Full string
+}
+
+// EstateKinds - признаки владения по умолчанию, пока не загружен справочник EstateStatus
+var EstateKinds = map[int]HouseKind{
+	0: {"д", "дом"}, // не определено - выводим как дом
+	1: {"влд", "владение"},
+	2: {"д", "дом"},
+	3: {"двлд", "домовладение"},
+	4: {"гар", "гараж"},
+	5: {"зд", "здание"},
+}
+
+// StructureKinds - признаки строения по умолчанию, пока не загружен справочник StructureStatus
+var StructureKinds = map[int]HouseKind{
+	0: {"стр", "строение"}, // не определено - выводим как строение
+	1: {"стр", "строение"},
+	2: {"соор", "сооружение"},
+	3: {"лит", "литер"},
+}
+
+// houseKeyword - часть номера и код признака для слова в строке номера дома
+type houseKeyword struct {
+	part   byte // h - дом, b - корпус, s - строение
+	status int
+}
+
+// houseKeywords - слова в строке номера дома, кроме наименований из справочников
+var houseKeywords = map[string]houseKeyword{
+	"д": {'h', 2}, "дом": {'h', 2},
+	"влд": {'h', 1}, "вл": {'h', 1}, "владение": {'h', 1},
+	"двлд": {'h', 3}, "домовл": {'h', 3}, "домовладение": {'h', 3},
+	"гар": {'h', 4}, "г-ж": {'h', 4}, "гараж": {'h', 4},
+	"зд": {'h', 5}, "здание": {'h', 5},
+	"к": {'b', 0}, "корп": {'b', 0}, "корпус": {'b', 0},
+	"стр": {'s', 1}, "с": {'s', 1}, "строение": {'s', 1},
+	"соор": {'s', 2}, "сооружение": {'s', 2},
+	"лит": {'s', 3}, "литер": {'s', 3}, "литера": {'s', 3},
+}
+
+// houseDictionary - признаки и слова, которыми пользуются разбор и вывод номеров
+type houseDictionary struct {
+	estate, structure map[int]HouseKind
+	keywords          map[string]houseKeyword
+}
+
+// houseKinds - текущий словарь; подменяется целиком, чтобы API читал его без блокировок
+var houseKinds atomic.Pointer[houseDictionary]
+
+func init() {
+	SetHouseKinds(nil, nil)
+}
+
+// SetHouseKinds - признаки владения и строения из справочников EstateStatus и StructureStatus
+// поверх значений по умолчанию; сокращения и наименования признаков распознаются при разборе
+func SetHouseKinds(estate, structure map[int]HouseKind) {
+	d := &houseDictionary{
+		estate:    merge(EstateKinds, estate),
+		structure: merge(StructureKinds, structure),
+		keywords:  maps.Clone(houseKeywords),
+	}
+	for part, kinds := range map[byte]map[int]HouseKind{'h': d.estate, 's': d.structure} {
+		for _, code := range slices.Sorted(maps.Keys(kinds)) {
+			for _, word := range []string{kinds[code].Short, kinds[code].Full} {
+				word = strings.ToLower(word)
+				if _, ok := d.keywords[word]; !ok && word != "" && code != 0 {
+					d.keywords[word] = houseKeyword{part, code}
+				}
+			}
+		}
+	}
+	houseKinds.Store(d)
+}
+
+// merge - признаки по умолчанию, дополненные и переопределенные справочником
+func merge(defaults, kinds map[int]HouseKind) map[int]HouseKind {
+	merged := maps.Clone(defaults)
+	for code, k := range kinds {
+		k.Short = strings.TrimSuffix(strings.TrimSpace(k.Short), ".")
+		k.Full = strings.ToLower(strings.TrimSpace(k.Full))
+		if k.Short == "" {
+			k.Short = k.Full
+		}
+		// "Не определено" (код 0) выводим как значение по умолчанию
+		if k.Short == "" || code == 0 {
+			continue
+		}
+		merged[code] = k
+	}
+	return merged
+}
+
+// Label - номер дома записи
+func (h House) Label() HouseLabel {
+	return HouseLabel{EstStatus: h.ESTStatus, HouseNum: h.HouseNum, BuildNum: h.BuildNum, StrStatus: h.STRStatus, StrucNum: h.StrucNum}
+}
+
+// String - номер с сокращениями: "д 7 к 2 стр 1", "влд 3"
+func (l HouseLabel) String() string {
+	return l.format(false)
+}
+
+// Full - номер с полными наименованиями: "дом 7 корпус 2 строение 1"
+func (l HouseLabel) Full() string {
+	return l.format(true)
+}
+
+func (l HouseLabel) format(full bool) string {
+	kinds := houseKinds.Load()
+	name := func(k HouseKind) string {
+		if full {
+			return k.Full
+		}
+		return k.Short
+	}
+
+	var parts []string
+	if l.HouseNum != "" {
+		kind, ok := kinds.estate[l.EstStatus]
+		if !ok {
+			kind = kinds.estate[0]
+		}
+		parts = append(parts, name(kind)+" "+l.HouseNum)
+	}
+	if l.BuildNum != "" {
+		parts = append(parts, name(HouseKind{"к", "корпус"})+" "+l.BuildNum)
+	}
+	if l.StrucNum != "" {
+		kind, ok := kinds.structure[l.StrStatus]
+		if !ok {
+			kind = kinds.structure[0]
+		}
+		parts = append(parts, name(kind)+" "+l.StrucNum)
+	}
+	return strings.Join(parts, " ")
+}
+
+// ParseHouseLabel - разбор строки номера дома: "д. 7, корп. 2, стр. 1", "7к2с1", "влд 3",
+// "дом 12а", "12 а"; число без слова в начале - номер дома. Если признак владения не указан,
+// EstStatus = 0
+func ParseHouseLabel(s string) (HouseLabel, error) {
+	var l HouseLabel
+	tokens := houseTokens(strings.ToLower(s))
+	if len(tokens) == 0 {
+		return l, fmt.Errorf("пустой номер дома")
+	}
+	keywords := houseKinds.Load().keywords
+
+	part, status := byte('h'), 0
+	keyword := false
+	var last *string // последний номер, к которому может относиться буква через пробел
+	for i, t := range tokens {
+		// буква через пробел после номера без буквы - часть номера: "12 а" -> 12а;
+		// буква-сокращение ("к", "с") считается буквой, только если за ней нет номера
+		if last != nil && !keyword && isLetter(t) && !unicode.IsLetter(lastRune(*last)) {
+			if _, ok := keywords[t]; !ok || i+1 == len(tokens) {
+				*last += t
+				last = nil
+				continue
+			}
+		}
+		last = nil
+
+		if kw, ok := keywords[t]; ok {
+			part, status, keyword = kw.part, kw.status, true
+			continue
+		}
+		// литер может быть буквой: "лит А"
+		litera := keyword && part == 's' && status == 3
+		if !unicode.IsDigit([]rune(t)[0]) && !litera {
+			return l, fmt.Errorf("номер дома %q: неизвестное слово %q", s, t)
+		}
+
+		var dst *string
+		switch part {
+		case 'h':
+			dst = &l.HouseNum
+			if keyword {
+				l.EstStatus = status
+			}
+		case 'b':
+			dst = &l.BuildNum
+		case 's':
+			dst = &l.StrucNum
+			l.StrStatus = status
+		}
+		if *dst != "" {
+			return l, fmt.Errorf("номер дома %q: часть указана дважды", s)
+		}
+		*dst = t
+		if !litera {
+			last = dst
+		}
+
+		// следующий номер без слова - корпус после дома, строение после корпуса
+		switch part {
+		case 'h':
+			part, status = 'b', 0
+		case 'b':
+			part, status = 's', 1
+		}
+		keyword = false
+	}
+
+	if l.HouseNum == "" && l.BuildNum == "" && l.StrucNum == "" {
+		return l, fmt.Errorf("номер дома %q: нет номера", s)
+	}
+	return l, nil
+}
+
+// isLetter - одна буква
+func isLetter(t string) bool {
+	r := []rune(t)
+	return len(r) == 1 && unicode.IsLetter(r[0])
+}
+
+// lastRune - последний символ строки
+func lastRune(s string) rune {
+	r := []rune(s)
+	return r[len(r)-1]
+}
+
+// houseTokens - слова и номера; номер - цифры с дробью через "/" и одной буквой ("12а", "7/1"),
+// буква, за которой идет цифра, - отдельное слово ("7к2" -> 7, к, 2)
+func houseTokens(s string) []string {
+	runes := []rune(s)
+	var tokens []string
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsDigit(r):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || runes[j] == '/' && j+1 < len(runes) && unicode.IsDigit(runes[j+1])) {
+				j++
+			}
+			if j < len(runes) && unicode.IsLetter(runes[j]) && (j+1 == len(runes) || !unicode.IsLetter(runes[j+1]) && !unicode.IsDigit(runes[j+1])) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		case unicode.IsLetter(r):
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || runes[j] == '-' && j+1 < len(runes) && unicode.IsLetter(runes[j+1])) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			i++
+		}
+	}
+	return tokens
+}
@@ -0,0 +1,85 @@
+package model
+
+import "testing"
+
+func TestParseHouseLabel(t *testing.T) {
+	tests := []struct {
+		in   string
+		want HouseLabel
+	}{
+		{"д. 7, корп. 2, стр. 1", HouseLabel{EstStatus: 2, HouseNum: "7", BuildNum: "2", StrStatus: 1, StrucNum: "1"}},
+		{"7к2с1", HouseLabel{HouseNum: "7", BuildNum: "2", StrStatus: 1, StrucNum: "1"}},
+		{"влд 3", HouseLabel{EstStatus: 1, HouseNum: "3"}},
+		{"дом 12а", HouseLabel{EstStatus: 2, HouseNum: "12а"}},
+		{"12 а", HouseLabel{HouseNum: "12а"}},
+		{"д 12 б к 1", HouseLabel{EstStatus: 2, HouseNum: "12б", BuildNum: "1"}},
+		{"7 к 2", HouseLabel{HouseNum: "7", BuildNum: "2"}},
+		{"7/1", HouseLabel{HouseNum: "7/1"}},
+		{"5 лит А", HouseLabel{HouseNum: "5", StrStatus: 3, StrucNum: "а"}},
+		{"12 соор 3", HouseLabel{HouseNum: "12", StrStatus: 2, StrucNum: "3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseHouseLabel(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHouseLabel(%q) = %+v, ожидается %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseHouseLabelErrors(t *testing.T) {
+	for _, in := range []string{"", " , ", "д", "дом номер 5", "д 5 д 6"} {
+		if got, err := ParseHouseLabel(in); err == nil {
+			t.Errorf("ParseHouseLabel(%q) = %+v, ожидается ошибка", in, got)
+		}
+	}
+}
+
+func TestHouseLabelString(t *testing.T) {
+	l := HouseLabel{EstStatus: 3, HouseNum: "7", BuildNum: "2", StrStatus: 2, StrucNum: "1"}
+	if got, want := l.String(), "двлд 7 к 2 соор 1"; got != want {
+		t.Errorf("String() = %q, ожидается %q", got, want)
+	}
+	if got, want := l.Full(), "домовладение 7 корпус 2 сооружение 1"; got != want {
+		t.Errorf("Full() = %q, ожидается %q", got, want)
+	}
+}
+
+func TestSetHouseKinds(t *testing.T) {
+	defer SetHouseKinds(nil, nil)
+	SetHouseKinds(map[int]HouseKind{
+		0:  {"", "Не определено"},
+		6:  {"шахта", "Шахта"},
+		14: {"ОНС", "Объект незавершенного строительства"},
+	}, map[int]HouseKind{
+		4: {"к.", "Корпус"},
+	})
+
+	tests := []struct {
+		label HouseLabel
+		short string
+	}{
+		{HouseLabel{EstStatus: 6, HouseNum: "1"}, "шахта 1"},
+		{HouseLabel{EstStatus: 14, HouseNum: "2"}, "ОНС 2"},
+		{HouseLabel{EstStatus: 0, HouseNum: "3"}, "д 3"},
+		{HouseLabel{EstStatus: 99, HouseNum: "4"}, "д 4"},
+		{HouseLabel{HouseNum: "5", StrStatus: 4, StrucNum: "1"}, "д 5 к 1"},
+	}
+	for _, tt := range tests {
+		if got := tt.label.String(); got != tt.short {
+			t.Errorf("String() = %q, ожидается %q", got, tt.short)
+		}
+	}
+
+	got, err := ParseHouseLabel("онс 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (HouseLabel{EstStatus: 14, HouseNum: "2"}); got != want {
+		t.Errorf("ParseHouseLabel(\"онс 2\") = %+v, ожидается %+v", got, want)
+	}
+}
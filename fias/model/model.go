@@ -0,0 +1,256 @@
+package model
+
+// ActualStatus - Статус актуальности ФИАС
+type ActualStatus struct {
+	ACTSTATID int    `xml:"ACTSTATID,attr"`
+	NAME      string `xml:"NAME,attr"`
+}
+
+// Object - Классификатор адресообразующих элементов
+type Object struct {
+	AOGUID     string  `xml:"AOGUID,attr"`
+	FORMALNAME string  `xml:"FORMALNAME,attr"`
+	REGIONCODE string  `xml:"REGIONCODE,attr"`
+	AUTOCODE   string  `xml:"AUTOCODE,attr"`
+	AREACODE   string  `xml:"AREACODE,attr"`
+	CITYCODE   string  `xml:"CITYCODE,attr"`
+	CTARCODE   string  `xml:"CTARCODE,attr"`
+	PLACECODE  string  `xml:"PLACECODE,attr"`
+	STREETCODE string  `xml:"STREETCODE,attr"`
+	EXTRCODE   string  `xml:"EXTRCODE,attr"`
+	SEXTCODE   string  `xml:"SEXTCODE,attr"`
+	OFFNAME    string  `xml:"OFFNAME,attr"`
+	POSTALCODE NullInt `xml:"POSTALCODE,attr"`
+	IFNSFL     string  `xml:"IFNSFL,attr"`
+	TERRIFNSFL string  `xml:"TERRIFNSFL,attr"`
+	IFNSUL     string  `xml:"IFNSUL,attr"`
+	TERRIFNSUL string  `xml:"TERRIFNSUL,attr"`
+	OKATO      string  `xml:"OKATO,attr"`
+	OKTMO      string  `xml:"OKTMO,attr"`
+	UPDATEDATE Date    `xml:"UPDATEDATE,attr"`
+	SHORTNAME  string  `xml:"SHORTNAME,attr"`
+	AOLEVEL    int     `xml:"AOLEVEL,attr"`
+	PARENTGUID string  `xml:"PARENTGUID,attr"`
+	AOID       string  `xml:"AOID,attr"`
+	PREVID     string  `xml:"PREVID,attr"`
+	NEXTID     string  `xml:"NEXTID,attr"`
+	CODE       string  `xml:"CODE,attr"`
+	PLAINCODE  string  `xml:"PLAINCODE,attr"`
+	ACTSTATUS  int     `xml:"ACTSTATUS,attr"`
+	CENTSTATUS int     `xml:"CENTSTATUS,attr"`
+	OPERSTATUS int     `xml:"OPERSTATUS,attr"`
+	CURRSTATUS int     `xml:"CURRSTATUS,attr"`
+	STARTDATE  Date    `xml:"STARTDATE,attr"`
+	ENDDATE    Date    `xml:"ENDDATE,attr"`
+	NORMDOC    string  `xml:"NORMDOC,attr"`
+	LIVESTATUS int     `xml:"LIVESTATUS,attr"`
+	CADNUM     string  `xml:"CADNUM,attr"`
+	DIVTYPE    int     `xml:"DIVTYPE,attr"`
+}
+
+// CenterStatus - Статус центра
+type CenterStatus struct {
+	CENTERSTID int    `xml:"CENTERSTID,attr"`
+	NAME       string `xml:"NAME,attr"`
+}
+
+// CurrentStatus - Статус актуальности КЛАДР 4.0
+type CurrentStatus struct {
+	CURENTSTID int    `xml:"CURENTSTID,attr"`
+	NAME       string `xml:"NAME,attr"`
+}
+
+// EstateStatus - Признак владения
+type EstateStatus struct {
+	ESTSTATID int    `xml:"ESTSTATID,attr"`
+	NAME      string `xml:"NAME,attr"`
+	SHORTNAME string `xml:"SHORTNAME,attr"`
+}
+
+// House - Сведения по номерам домов улиц городов и населенных пунктов
+type House struct {
+	PostalCode NullInt `xml:"POSTALCODE,attr"`
+	RegionCode string  `xml:"REGIONCODE,attr"`
+	IFNSFL     string  `xml:"IFNSFL,attr"`
+	TerrIFNSFL string  `xml:"TERRIFNSFL,attr"`
+	IFNSUL     string  `xml:"IFNSUL,attr"`
+	TerrIFNSUL string  `xml:"TERRIFNSUL,attr"`
+	OKATO      string  `xml:"OKATO,attr"`
+	OKTMO      string  `xml:"OKTMO,attr"`
+	UPDATEDATE Date    `xml:"UPDATEDATE,attr"`
+	HouseNum   string  `xml:"HOUSENUM,attr"`
+	ESTStatus  int     `xml:"ESTSTATUS,attr"`
+	//ESTStatus string `xml:"ESTSTATUS,attr"`
+	BuildNum  string `xml:"BUILDNUM,attr"`
+	StrucNum  string `xml:"STRUCNUM,attr"`
+	STRStatus int    `xml:"STRSTATUS,attr"`
+	//STRStatus string `xml:"STRSTATUS,attr"`
+	HouseID    string `xml:"HOUSEID,attr"`
+	HouseGUID  string `xml:"HOUSEGUID,attr"`
+	AOGUID     string `xml:"AOGUID,attr"`
+	STARTDATE  Date   `xml:"STARTDATE,attr"`
+	ENDDATE    Date   `xml:"ENDDATE,attr"`
+	StatStatus int    `xml:"STATSTATUS,attr"`
+	//StatStatus string `xml:"STATSTATUS,attr"`
+	NormDoc string `xml:"NORMDOC,attr"`
+	Counter int    `xml:"COUNTER,attr"`
+	//Counter string `xml:"COUNTER,attr"`
+	CadNum  string `xml:"CADNUM,attr"`
+	DviType int    `xml:"DVITYPE,attr"`
+	//DviType string `xml:"DVITYPE,attr"`
+}
+
+// HouseInterval - Интервалы домов
+type HouseInterval struct {
+	POSTALCODE NullInt `xml:"POSTALCODE,attr"`
+	IFNSFL     string  `xml:"IFNSFL,attr"`
+	TERRIFNSFL string  `xml:"TERRIFNSFL,attr"`
+	IFNSUL     string  `xml:"IFNSUL,attr"`
+	TERRIFNSUL string  `xml:"TERRIFNSUL,attr"`
+	OKATO      string  `xml:"OKATO,attr"`
+	OKTMO      string  `xml:"OKTMO,attr"`
+	UPDATEDATE Date    `xml:"UPDATEDATE,attr"`
+	INTSTART   int     `xml:"INTSTART,attr"`
+	INTEND     int     `xml:"INTEND,attr"`
+	HOUSEINTID string  `xml:"HOUSEINTID,attr"`
+	INTGUID    string  `xml:"INTGUID,attr"`
+	AOGUID     string  `xml:"AOGUID,attr"`
+	STARTDATE  Date    `xml:"STARTDATE,attr"`
+	ENDDATE    Date    `xml:"ENDDATE,attr"`
+	INTSTATUS  int     `xml:"INTSTATUS,attr"`
+	NORMDOC    string  `xml:"NORMDOC,attr"`
+	COUNTER    int     `xml:"COUNTER,attr"`
+}
+
+// HouseStateStatus - Статус состояния домов
+type HouseStateStatus struct {
+	HOUSESTID int    `xml:"HOUSESTID,attr"`
+	NAME      string `xml:"NAME,attr"`
+}
+
+// IntervalStatus - Статус интервала домов
+type IntervalStatus struct {
+	INTVSTATID int    `xml:"INTVSTATID,attr"`
+	NAME       string `xml:"NAME,attr"`
+}
+
+// Landmark - Описание мест расположения  имущественных объектов
+type Landmark struct {
+	LOCATION   string  `xml:"LOCATION,attr"`
+	REGIONCODE string  `xml:"REGIONCODE,attr"`
+	POSTALCODE NullInt `xml:"POSTALCODE,attr"`
+	IFNSFL     string  `xml:"IFNSFL,attr"`
+	TERRIFNSFL string  `xml:"TERRIFNSFL,attr"`
+	IFNSUL     string  `xml:"IFNSUL,attr"`
+	TERRIFNSUL string  `xml:"TERRIFNSUL,attr"`
+	OKATO      string  `xml:"OKATO,attr"`
+	OKTMO      string  `xml:"OKTMO,attr"`
+	UPDATEDATE Date    `xml:"UPDATEDATE,attr"`
+	LANDID     string  `xml:"LANDID,attr"`
+	LANDGUID   string  `xml:"LANDGUID,attr"`
+	AOGUID     string  `xml:"AOGUID,attr"`
+	STARTDATE  Date    `xml:"STARTDATE,attr"`
+	ENDDATE    Date    `xml:"ENDDATE,attr"`
+	NORMDOC    string  `xml:"NORMDOC,attr"`
+	CADNUM     string  `xml:"CADNUM,attr"`
+}
+
+// NormativeDocumentType - Тип нормативного документа
+type NormativeDocumentType struct {
+	NDTYPEID int    `xml:"NDTYPEID,attr"`
+	NAME     string `xml:"NAME,attr"`
+}
+
+// NormativeDocument - Сведения по нормативному документу, являющемуся основанием присвоения адресному элементу наименования
+type NormativeDocument struct {
+	NORMDOCID string `xml:"NORMDOCID,attr"`
+	DOCNAME   string `xml:"DOCNAME,attr"`
+	DOCDATE   Date   `xml:"DOCDATE,attr"`
+	DOCNUM    string `xml:"DOCNUM,attr"`
+	DOCTYPE   int    `xml:"DOCTYPE,attr"`
+	DOCIMGID  string `xml:"DOCIMGID,attr"`
+}
+
+// OperationStatus - Статус действия
+type OperationStatus struct {
+	OPERSTATID int    `xml:"OPERSTATID,attr"`
+	NAME       string `xml:"NAME,attr"`
+}
+
+// Room - Классификатор помещениях
+type Room struct {
+	RoomGuid   string  `xml:"ROOMGUID,attr"`
+	FlatNumber string  `xml:"FLATNUMBER,attr"`
+	FlatType   int     `xml:"FLATTYPE,attr"`
+	RoomNumber string  `xml:"ROOMNUMBER,attr"`
+	RoomType   int     `xml:"ROOMTYPE,attr"`
+	RegionCode string  `xml:"REGIONCODE,attr"`
+	PostalCode NullInt `xml:"POSTALCODE,attr"`
+	UpdateDate Date    `xml:"UPDATEDATE,attr"`
+	HouseGuid  string  `xml:"HOUSEGUID,attr"`
+	RoomId     string  `xml:"ROOMID,attr"`
+	PrevId     string  `xml:"PREVID,attr"`
+	NextId     string  `xml:"NEXTID,attr"`
+	StartDate  Date    `xml:"STARTDATE,attr"`
+	EndDate    Date    `xml:"ENDDATE,attr"`
+	LiveStatus int     `xml:"LIVESTATUS,attr"`
+	NormDoc    string  `xml:"NORMDOC,attr"`
+	OperStatus int     `xml:"OPERSTATUS,attr"`
+	CadNum     string  `xml:"CADNUM,attr"`
+	RoomCadNum string  `xml:"ROOMCADNUM,attr"`
+}
+
+// AddressObjectType - Тип адресного объекта
+type AddressObjectType struct {
+	LEVEL    int    `xml:"LEVEL,attr"`
+	SCNAME   string `xml:"SCNAME,attr"`
+	SOCRNAME string `xml:"SOCRNAME,attr"`
+	KODTST   string `xml:"KOD_T_ST,attr"`
+}
+
+// Stead - Классификатор земельных участков
+type Stead struct {
+	STEADGUID  string  `xml:"STEADGUID,attr"`
+	NUMBER     string  `xml:"NUMBER,attr"`
+	REGIONCODE string  `xml:"REGIONCODE,attr"`
+	POSTALCODE NullInt `xml:"POSTALCODE,attr"`
+	IFNSFL     string  `xml:"IFNSFL,attr"`
+	TERRIFNSFL string  `xml:"TERRIFNSFL,attr"`
+	IFNSUL     string  `xml:"IFNSUL,attr"`
+	TERRIFNSUL string  `xml:"TERRIFNSUL,attr"`
+	OKATO      string  `xml:"OKATO,attr"`
+	OKTMO      string  `xml:"OKTMO,attr"`
+	UPDATEDATE Date    `xml:"UPDATEDATE,attr"`
+	PARENTGUID string  `xml:"PARENTGUID,attr"`
+	STEADID    string  `xml:"STEADID,attr"`
+	PREVID     string  `xml:"PREVID,attr"`
+	NEXTID     string  `xml:"NEXTID,attr"`
+	OPERSTATUS int     `xml:"OPERSTATUS,attr"`
+	STARTDATE  Date    `xml:"STARTDATE,attr"`
+	ENDDATE    Date    `xml:"ENDDATE,attr"`
+	NORMDOC    string  `xml:"NORMDOC,attr"`
+	LIVESTATUS int     `xml:"LIVESTATUS,attr"`
+	CADNUM     string  `xml:"CADNUM,attr"`
+	DIVTYPE    int     `xml:"DIVTYPE,attr"`
+}
+
+// StructureStatus - Признак строения
+type StructureStatus struct {
+	STRSTATID int    `xml:"STRSTATID,attr"`
+	NAME      string `xml:"NAME,attr"`
+	SHORTNAME string `xml:"SHORTNAME,attr"`
+}
+
+// FlatType - Тип помещения
+type FlatType struct {
+	FLTYPEID  int    `xml:"FLTYPEID,attr"`
+	NAME      string `xml:"NAME,attr"`
+	SHORTNAME string `xml:"SHORTNAME,attr"`
+}
+
+// RoomType - Тип комнаты
+type RoomType struct {
+	RMTYPEID  int    `xml:"RMTYPEID,attr"`
+	NAME      string `xml:"NAME,attr"`
+	SHORTNAME string `xml:"SHORTNAME,attr"`
+}
@@ -0,0 +1,352 @@
+package model
+
+// Record - запись ФИАС: имя XML элемента и значения колонок таблицы
+type Record interface {
+	Element() string
+	Row() map[string]interface{}
+}
+
+// Element - имя XML элемента записи
+func (ActualStatus) Element() string { return "ActualStatus" }
+
+// Row - значения колонок таблицы
+func (a ActualStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"actstatid": a.ACTSTATID,
+		"name":      a.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (Object) Element() string { return "Object" }
+
+// Row - значения колонок таблицы
+func (o Object) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"aoguid":     o.AOGUID,
+		"formalname": o.FORMALNAME,
+		"regioncode": o.REGIONCODE,
+		"autocode":   o.AUTOCODE,
+		"areacode":   o.AREACODE,
+		"citycode":   o.CITYCODE,
+		"ctarcode":   o.CTARCODE,
+		"placecode":  o.PLACECODE,
+		"streetcode": o.STREETCODE,
+		"extrcode":   o.EXTRCODE,
+		"sextcode":   o.SEXTCODE,
+		"offname":    o.OFFNAME,
+		"postalcode": o.POSTALCODE,
+		"ifnsfl":     o.IFNSFL,
+		"terrifnsfl": o.TERRIFNSFL,
+		"ifnsul":     o.IFNSUL,
+		"terrifnsul": o.TERRIFNSUL,
+		"okato":      o.OKATO,
+		"oktmo":      o.OKTMO,
+		"updatedate": o.UPDATEDATE,
+		"shortname":  o.SHORTNAME,
+		"aolevel":    o.AOLEVEL,
+		"parentguid": o.PARENTGUID,
+		"aoid":       o.AOID,
+		"previd":     o.PREVID,
+		"nextid":     o.NEXTID,
+		"code":       o.CODE,
+		"plaincode":  o.PLAINCODE,
+		"actstatus":  o.ACTSTATUS,
+		"centstatus": o.CENTSTATUS,
+		"operstatus": o.OPERSTATUS,
+		"currstatus": o.CURRSTATUS,
+		"startdate":  o.STARTDATE,
+		"enddate":    o.ENDDATE,
+		"normdoc":    o.NORMDOC,
+		"livestatus": o.LIVESTATUS,
+		"cadnum":     o.CADNUM,
+		"divtype":    o.DIVTYPE,
+	}
+}
+
+// Element - имя XML элемента записи
+func (CenterStatus) Element() string { return "CenterStatus" }
+
+// Row - значения колонок таблицы
+func (c CenterStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"centerstid": c.CENTERSTID,
+		"name":       c.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (CurrentStatus) Element() string { return "CurrentStatus" }
+
+// Row - значения колонок таблицы
+func (c CurrentStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"curentstid": c.CURENTSTID,
+		"name":       c.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (EstateStatus) Element() string { return "EstateStatus" }
+
+// Row - значения колонок таблицы
+func (e EstateStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"eststatid": e.ESTSTATID,
+		"name":      e.NAME,
+		"shortname": e.SHORTNAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (House) Element() string { return "House" }
+
+// Row - значения колонок таблицы
+func (h House) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"postalcode": h.PostalCode,
+		"regioncode": h.RegionCode,
+		"ifnsfl":     h.IFNSFL,
+		"terrifnsfl": h.TerrIFNSFL,
+		"ifnsul":     h.IFNSUL,
+		"terrifnsul": h.TerrIFNSUL,
+		"okato":      h.OKATO,
+		"oktmo":      h.OKTMO,
+		"updatedate": h.UPDATEDATE,
+		"housenum":   h.HouseNum,
+		"eststatus":  h.ESTStatus,
+		"buildnum":   h.BuildNum,
+		"strucnum":   h.StrucNum,
+		"strstatus":  h.STRStatus,
+		"houseid":    h.HouseID,
+		"houseguid":  h.HouseGUID,
+		"aoguid":     h.AOGUID,
+		"startdate":  h.STARTDATE,
+		"enddate":    h.ENDDATE,
+		"statstatus": h.StatStatus,
+		"normdoc":    h.NormDoc,
+		"counter":    h.Counter,
+		"cadnum":     h.CadNum,
+		"dvitype":    h.DviType,
+	}
+}
+
+// Element - имя XML элемента записи
+func (HouseInterval) Element() string { return "HouseInterval" }
+
+// Row - значения колонок таблицы
+func (h HouseInterval) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"postalcode": h.POSTALCODE,
+		"ifnsfl":     h.IFNSFL,
+		"terrifnsfl": h.TERRIFNSFL,
+		"ifnsul":     h.IFNSUL,
+		"terrifnsul": h.TERRIFNSUL,
+		"okato":      h.OKATO,
+		"oktmo":      h.OKTMO,
+		"updatedate": h.UPDATEDATE,
+		"intstart":   h.INTSTART,
+		"intend":     h.INTEND,
+		"houseintid": h.HOUSEINTID,
+		"intguid":    h.INTGUID,
+		"aoguid":     h.AOGUID,
+		"startdate":  h.STARTDATE,
+		"enddate":    h.ENDDATE,
+		"intstatus":  h.INTSTATUS,
+		"normdoc":    h.NORMDOC,
+		"counter":    h.COUNTER,
+	}
+}
+
+// Element - имя XML элемента записи
+func (HouseStateStatus) Element() string { return "HouseStateStatus" }
+
+// Row - значения колонок таблицы
+func (h HouseStateStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"housestid": h.HOUSESTID,
+		"name":      h.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (IntervalStatus) Element() string { return "IntervalStatus" }
+
+// Row - значения колонок таблицы
+func (i IntervalStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"intvstatid": i.INTVSTATID,
+		"name":       i.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (Landmark) Element() string { return "Landmark" }
+
+// Row - значения колонок таблицы
+func (l Landmark) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"location":   l.LOCATION,
+		"regioncode": l.REGIONCODE,
+		"postalcode": l.POSTALCODE,
+		"ifnsfl":     l.IFNSFL,
+		"terrifnsfl": l.TERRIFNSFL,
+		"ifnsul":     l.IFNSUL,
+		"terrifnsul": l.TERRIFNSUL,
+		"okato":      l.OKATO,
+		"oktmo":      l.OKTMO,
+		"updatedate": l.UPDATEDATE,
+		"landid":     l.LANDID,
+		"landguid":   l.LANDGUID,
+		"aoguid":     l.AOGUID,
+		"startdate":  l.STARTDATE,
+		"enddate":    l.ENDDATE,
+		"normdoc":    l.NORMDOC,
+		"cadnum":     l.CADNUM,
+	}
+}
+
+// Element - имя XML элемента записи
+func (NormativeDocumentType) Element() string { return "NormativeDocumentType" }
+
+// Row - значения колонок таблицы
+func (n NormativeDocumentType) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"ndtypeid": n.NDTYPEID,
+		"name":     n.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (NormativeDocument) Element() string { return "NormativeDocument" }
+
+// Row - значения колонок таблицы
+func (n NormativeDocument) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"normdocid": n.NORMDOCID,
+		"docname":   n.DOCNAME,
+		"docdate":   n.DOCDATE,
+		"docnum":    n.DOCNUM,
+		"doctype":   n.DOCTYPE,
+		"docimgid":  n.DOCIMGID,
+	}
+}
+
+// Element - имя XML элемента записи
+func (OperationStatus) Element() string { return "OperationStatus" }
+
+// Row - значения колонок таблицы
+func (o OperationStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"operstatid": o.OPERSTATID,
+		"name":       o.NAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (Room) Element() string { return "Room" }
+
+// Row - значения колонок таблицы
+func (r Room) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"roomguid":   r.RoomGuid,
+		"flatnumber": r.FlatNumber,
+		"flattype":   r.FlatType,
+		"roomnumber": r.RoomNumber,
+		"roomtype":   r.RoomType,
+		"regioncode": r.RegionCode,
+		"postalcode": r.PostalCode,
+		"updatedate": r.UpdateDate,
+		"houseguid":  r.HouseGuid,
+		"roomid":     r.RoomId,
+		"previd":     r.PrevId,
+		"nextid":     r.NextId,
+		"startdate":  r.StartDate,
+		"enddate":    r.EndDate,
+		"livestatus": r.LiveStatus,
+		"normdoc":    r.NormDoc,
+		"operstatus": r.OperStatus,
+		"cadnum":     r.CadNum,
+		"roomcadnum": r.RoomCadNum,
+	}
+}
+
+// Element - имя XML элемента записи
+func (AddressObjectType) Element() string { return "AddressObjectType" }
+
+// Row - значения колонок таблицы
+func (a AddressObjectType) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"level":    a.LEVEL,
+		"scname":   a.SCNAME,
+		"socrname": a.SOCRNAME,
+		"kodtst":   a.KODTST,
+	}
+}
+
+// Element - имя XML элемента записи
+func (Stead) Element() string { return "Stead" }
+
+// Row - значения колонок таблицы
+func (s Stead) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"steadguid":  s.STEADGUID,
+		"number":     s.NUMBER,
+		"regioncode": s.REGIONCODE,
+		"postalcode": s.POSTALCODE,
+		"ifnsfl":     s.IFNSFL,
+		"terrifnsfl": s.TERRIFNSFL,
+		"ifnsul":     s.IFNSUL,
+		"terrifnsul": s.TERRIFNSUL,
+		"okato":      s.OKATO,
+		"oktmo":      s.OKTMO,
+		"updatedate": s.UPDATEDATE,
+		"parentguid": s.PARENTGUID,
+		"steadid":    s.STEADID,
+		"previd":     s.PREVID,
+		"nextid":     s.NEXTID,
+		"operstatus": s.OPERSTATUS,
+		"startdate":  s.STARTDATE,
+		"enddate":    s.ENDDATE,
+		"normdoc":    s.NORMDOC,
+		"livestatus": s.LIVESTATUS,
+		"cadnum":     s.CADNUM,
+		"divtype":    s.DIVTYPE,
+	}
+}
+
+// Element - имя XML элемента записи
+func (StructureStatus) Element() string { return "StructureStatus" }
+
+// Row - значения колонок таблицы
+func (s StructureStatus) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"strstatid": s.STRSTATID,
+		"name":      s.NAME,
+		"shortname": s.SHORTNAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (FlatType) Element() string { return "FlatType" }
+
+// Row - значения колонок таблицы
+func (f FlatType) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"fltypeid":  f.FLTYPEID,
+		"name":      f.NAME,
+		"shortname": f.SHORTNAME,
+	}
+}
+
+// Element - имя XML элемента записи
+func (RoomType) Element() string { return "RoomType" }
+
+// Row - значения колонок таблицы
+func (r RoomType) Row() map[string]interface{} {
+	return map[string]interface{}{
+		"rmtypeid":  r.RMTYPEID,
+		"name":      r.NAME,
+		"shortname": r.SHORTNAME,
+	}
+}
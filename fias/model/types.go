@@ -0,0 +1,90 @@
+package model
+
+import (
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout - формат дат в XML ФИАС
+const dateLayout = "2006-01-02"
+
+// Date - дата из атрибута XML, пустой атрибут - NULL в БД
+type Date struct {
+	sql.NullTime
+}
+
+// NullInt - число из атрибута XML, пустой атрибут - NULL в БД
+type NullInt struct {
+	sql.NullInt64
+}
+
+// UnmarshalXMLAttr - разбор даты вида 2011-09-13 (допускается время через T)
+func (d *Date) UnmarshalXMLAttr(attr xml.Attr) error {
+	value := strings.TrimSpace(attr.Value)
+	if value == "" {
+		d.Time, d.Valid = time.Time{}, false
+		return nil
+	}
+	if i := strings.IndexByte(value, 'T'); i > 0 {
+		value = value[:i]
+	}
+
+	t, err := time.Parse(dateLayout, value)
+	if err != nil {
+		return fmt.Errorf("атрибут %s: неверная дата %q", attr.Name.Local, attr.Value)
+	}
+	d.Time, d.Valid = t, true
+	return nil
+}
+
+// String - дата в формате ФИАС или пустая строка для NULL
+func (d Date) String() string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Time.Format(dateLayout)
+}
+
+// MarshalJSON - дата в формате ФИАС или null
+func (d Date) MarshalJSON() ([]byte, error) {
+	if !d.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// UnmarshalXMLAttr - разбор целого числа
+func (n *NullInt) UnmarshalXMLAttr(attr xml.Attr) error {
+	value := strings.TrimSpace(attr.Value)
+	if value == "" {
+		n.Int64, n.Valid = 0, false
+		return nil
+	}
+
+	v, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("атрибут %s: неверное число %q", attr.Name.Local, attr.Value)
+	}
+	n.Int64, n.Valid = v, true
+	return nil
+}
+
+// String - число или пустая строка для NULL
+func (n NullInt) String() string {
+	if !n.Valid {
+		return ""
+	}
+	return strconv.FormatInt(n.Int64, 10)
+}
+
+// MarshalJSON - число или null
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return []byte(n.String()), nil
+}
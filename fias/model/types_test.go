@@ -0,0 +1,108 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestDateUnmarshalXMLAttr(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+		want  time.Time
+		err   bool
+	}{
+		{"", false, time.Time{}, false},
+		{"  ", false, time.Time{}, false},
+		{"2011-09-13", true, time.Date(2011, 9, 13, 0, 0, 0, 0, time.UTC), false},
+		{"2011-09-13T00:00:00", true, time.Date(2011, 9, 13, 0, 0, 0, 0, time.UTC), false},
+		{"13.09.2011", false, time.Time{}, true},
+		{"2011-13-01", false, time.Time{}, true},
+		{"вчера", false, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		d := Date{}
+		d.Time, d.Valid = time.Now(), true // прежнее значение не должно остаться
+		err := d.UnmarshalXMLAttr(xml.Attr{Name: xml.Name{Local: "STARTDATE"}, Value: tt.value})
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: нет ошибки, получено %v", tt.value, d)
+			}
+			continue
+		}
+		if err != nil || d.Valid != tt.valid || !d.Time.Equal(tt.want) {
+			t.Errorf("%q: %v, %v, %v; ожидается %v, %v", tt.value, d.Time, d.Valid, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestNullIntUnmarshalXMLAttr(t *testing.T) {
+	tests := []struct {
+		value string
+		valid bool
+		want  int64
+		err   bool
+	}{
+		{"", false, 0, false},
+		{"101000", true, 101000, false},
+		{" 7 ", true, 7, false},
+		{"-1", true, -1, false},
+		{"12а", false, 0, true},
+		{"1.5", false, 0, true},
+		{"99999999999999999999", false, 0, true},
+	}
+	for _, tt := range tests {
+		n := NullInt{}
+		n.Int64, n.Valid = 42, true
+		err := n.UnmarshalXMLAttr(xml.Attr{Name: xml.Name{Local: "POSTALCODE"}, Value: tt.value})
+		if tt.err {
+			if err == nil {
+				t.Errorf("%q: нет ошибки, получено %v", tt.value, n)
+			}
+			continue
+		}
+		if err != nil || n.Valid != tt.valid || n.Int64 != tt.want {
+			t.Errorf("%q: %d, %v, %v; ожидается %d, %v", tt.value, n.Int64, n.Valid, err, tt.want, tt.valid)
+		}
+	}
+}
+
+// TestTypedAttributes - пустые атрибуты записи становятся NULL, ошибка в атрибуте
+// называет его
+func TestTypedAttributes(t *testing.T) {
+	var h House
+	data := `<House HOUSEGUID="a" POSTALCODE="" STARTDATE="2019-05-14" ENDDATE="" />`
+	if err := xml.Unmarshal([]byte(data), &h); err != nil {
+		t.Fatal(err)
+	}
+	if h.PostalCode.Valid || !h.STARTDATE.Valid || h.ENDDATE.Valid {
+		t.Errorf("запись %+v", h)
+	}
+
+	// в БД пустые атрибуты пишутся как NULL
+	for _, column := range []string{"postalcode", "enddate"} {
+		valuer, ok := h.Row()[column].(driver.Valuer)
+		if !ok {
+			t.Fatalf("%s: %T не driver.Valuer", column, h.Row()[column])
+		}
+		if v, err := valuer.Value(); v != nil || err != nil {
+			t.Errorf("%s = %v, %v; ожидается NULL", column, v, err)
+		}
+	}
+
+	out, err := json.Marshal(struct {
+		Code NullInt
+		Date Date
+	}{h.PostalCode, h.STARTDATE})
+	if err != nil || string(out) != `{"Code":null,"Date":"2019-05-14"}` {
+		t.Errorf("JSON %s, %v", out, err)
+	}
+
+	err = xml.Unmarshal([]byte(`<House POSTALCODE="12а" />`), &h)
+	if err == nil || err.Error() != `атрибут POSTALCODE: неверное число "12а"` {
+		t.Errorf("ошибка %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// cadastralPattern - кадастровый номер АА:ББ:ККККККК:НН (округ, район, квартал, номер)
+var cadastralPattern = regexp.MustCompile(`^(\d{1,2}):(\d{1,2}):(\d{6,7}):(\d+)$`)
+
+// ErrInvalidCadastral - строка не является кадастровым номером
+var ErrInvalidCadastral = errors.New("неверный кадастровый номер")
+
+// CadastralMatch - запись ФИАС с кадастровым номером
+type CadastralMatch struct {
+	Kind     string   `json:"kind"` // house, room, stead, landmark
+	GUID     string   `json:"guid"`
+	Field    string   `json:"field"` // cadnum или roomcadnum
+	Location string   `json:"location,omitempty"`
+	Address  *Address `json:"address"`
+}
+
+// NormalizeCadastral - приводим номер к виду 77:01:0001001:1234: убираем пробелы,
+// заменяем похожие на двоеточие разделители, дополняем округ и район до двух цифр
+func NormalizeCadastral(number string) (string, error) {
+	number = strings.Map(func(r rune) rune {
+		switch {
+		case r == ' ' || r == '\t' || r == '\u00a0':
+			return -1
+		case r == '：' || r == ';' || r == '.':
+			return ':'
+		}
+		return r
+	}, number)
+
+	m := cadastralPattern.FindStringSubmatch(number)
+	if m == nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCadastral, number)
+	}
+	return fmt.Sprintf("%02s:%02s:%s:%s", m[1], m[2], m[3], m[4]), nil
+}
+
+// ByCadastral - действующие дома, помещения, участки и ориентиры с кадастровым номером number и их адреса
+func (s *Store) ByCadastral(ctx context.Context, number string) ([]CadastralMatch, error) {
+	number, err := NormalizeCadastral(number)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	sources := []struct {
+		kind, field, query string
+		address            func(ctx context.Context, guid string, at time.Time) (*Address, error)
+	}{
+		{"house", "cadnum", "SELECT houseguid, '' FROM house WHERE cadnum = $1 AND " + valid + ";", s.HouseAt},
+		{"room", "roomcadnum", "SELECT roomguid, '' FROM rooms WHERE roomcadnum = $1 AND " + valid + ";", s.RoomAt},
+		{"room", "cadnum", "SELECT roomguid, '' FROM rooms WHERE cadnum = $1 AND " + valid + ";", s.RoomAt},
+		{"stead", "cadnum", "SELECT steadguid, '' FROM steads WHERE cadnum = $1 AND " + valid + ";", s.SteadAt},
+		{"landmark", "cadnum", "SELECT aoguid, coalesce(location, '') FROM landmark WHERE cadnum = $1 AND " + valid + ";", s.AddressAt},
+	}
+
+	var matches []CadastralMatch
+	for _, src := range sources {
+		rows, err := s.DB.QueryContext(ctx, src.query, number, day(now))
+		if err != nil {
+			return nil, err
+		}
+		var found []CadastralMatch
+		for rows.Next() {
+			m := CadastralMatch{Kind: src.kind, Field: src.field}
+			if err := rows.Scan(&m.GUID, &m.Location); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			found = append(found, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, m := range found {
+			if m.Address, err = src.address(ctx, m.GUID, now); err != nil && !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+			matches = append(matches, m)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("кадастровый номер %s: %w", number, ErrNotFound)
+	}
+	return matches, nil
+}
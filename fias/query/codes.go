@@ -0,0 +1,69 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Code - значение кода и GUID записи, у которой оно заполнено
+type Code struct {
+	Value     string `json:"value"`
+	Source    string `json:"source"`
+	Inherited bool   `json:"inherited"`
+}
+
+// Codes - действующие ОКТМО, ОКАТО и коды ИФНС адреса
+type Codes struct {
+	GUID   string `json:"guid"`
+	OKTMO  *Code  `json:"oktmo"`
+	OKATO  *Code  `json:"okato"`
+	IFNSFL *Code  `json:"ifnsfl"`
+	IFNSUL *Code  `json:"ifnsul"`
+}
+
+// complete - все коды найдены
+func (c *Codes) complete() bool {
+	return c.OKTMO != nil && c.OKATO != nil && c.IFNSFL != nil && c.IFNSUL != nil
+}
+
+// fill - заполняем пустые коды значениями записи source
+func (c *Codes) fill(source, oktmo, okato, ifnsfl, ifnsul string) {
+	set := func(dst **Code, value string) {
+		if *dst == nil && value != "" {
+			*dst = &Code{Value: value, Source: source, Inherited: source != c.GUID}
+		}
+	}
+	set(&c.OKTMO, oktmo)
+	set(&c.OKATO, okato)
+	set(&c.IFNSFL, ifnsfl)
+	set(&c.IFNSUL, ifnsul)
+}
+
+// CodesOf - ОКТМО, ОКАТО, ИФНС ФЛ и ИФНС ЮЛ помещения, дома, участка или адресного объекта guid;
+// пустые у записи коды наследуются по цепочке помещение -> дом -> адресный объект -> родители
+func (s *Store) CodesOf(ctx context.Context, guid string) (*Codes, error) {
+	now := time.Now()
+	codes := &Codes{GUID: guid}
+
+	a, err := s.Resolve(ctx, guid, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if h := a.House; h != nil {
+		codes.fill(h.HouseGUID, h.OKTMO, h.OKATO, h.IFNSFL, h.IFNSUL)
+	}
+	if st := a.Stead; st != nil {
+		codes.fill(st.SteadGUID, st.OKTMO, st.OKATO, st.IFNSFL, st.IFNSUL)
+	}
+	for i := len(a.Objects) - 1; i >= 0 && !codes.complete(); i-- {
+		o := a.Objects[i]
+		codes.fill(o.AOGUID, o.OKTMO, o.OKATO, o.IFNSFL, o.IFNSUL)
+	}
+
+	if codes.OKTMO == nil && codes.OKATO == nil && codes.IFNSFL == nil && codes.IFNSUL == nil {
+		return nil, fmt.Errorf("коды %s: %w", guid, ErrNotFound)
+	}
+	return codes, nil
+}
@@ -0,0 +1,33 @@
+package query
+
+import "testing"
+
+func TestCodesFill(t *testing.T) {
+	codes := &Codes{GUID: "дом"}
+	// дом -> улица -> город: пустые коды берутся у ближайшего предка
+	codes.fill("дом", "45380000", "", "", "")
+	codes.fill("улица", "11111111", "45286560000", "7701", "")
+	if codes.complete() {
+		t.Fatal("коды заполнены без ИФНС ЮЛ")
+	}
+	codes.fill("город", "22222222", "45000000000", "7700", "7746")
+	if !codes.complete() {
+		t.Fatal("коды не заполнены")
+	}
+
+	tests := []struct {
+		name string
+		got  *Code
+		want Code
+	}{
+		{"oktmo", codes.OKTMO, Code{Value: "45380000", Source: "дом"}},
+		{"okato", codes.OKATO, Code{Value: "45286560000", Source: "улица", Inherited: true}},
+		{"ifnsfl", codes.IFNSFL, Code{Value: "7701", Source: "улица", Inherited: true}},
+		{"ifnsul", codes.IFNSUL, Code{Value: "7746", Source: "город", Inherited: true}},
+	}
+	for _, tt := range tests {
+		if *tt.got != tt.want {
+			t.Errorf("%s = %+v, ожидается %+v", tt.name, *tt.got, tt.want)
+		}
+	}
+}
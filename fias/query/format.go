@@ -0,0 +1,170 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Способ вывода типа адресного объекта
+const (
+	NamesShort = "short" // сокращение SHORTNAME: "ул Ленина"
+	NamesFull  = "full"  // полное наименование SOCRNAME из address_object_type: "Улица Ленина"
+	NamesNone  = "none"  // без типа: "Ленина"
+)
+
+// Порядок частей адреса
+const (
+	OrderAsc  = "asc"  // от региона к помещению
+	OrderDesc = "desc" // от помещения к региону (почтовый адрес)
+)
+
+// Template - шаблон форматирования адреса
+type Template struct {
+	Order      string
+	Names      string
+	PostalCode bool // индекс в начале (asc) или в конце (desc)
+	Region     bool // включать регион (AOLEVEL 1)
+	Separator  string
+}
+
+// Templates - готовые шаблоны
+var Templates = map[string]Template{
+	"oneline":  {Order: OrderAsc, Names: NamesShort, Region: true, Separator: ", "},
+	"postal":   {Order: OrderDesc, Names: NamesShort, PostalCode: true, Region: true, Separator: ", "},
+	"official": {Order: OrderAsc, Names: NamesFull, PostalCode: true, Region: true, Separator: ", "},
+	"short":    {Order: OrderAsc, Names: NamesShort, Separator: ", "},
+}
+
+// Validate - проверяем значения шаблона
+func (t Template) Validate() error {
+	switch t.Order {
+	case OrderAsc, OrderDesc:
+	default:
+		return fmt.Errorf("order: ожидается asc или desc, получено %q", t.Order)
+	}
+	switch t.Names {
+	case NamesShort, NamesFull, NamesNone:
+	default:
+		return fmt.Errorf("names: ожидается short, full или none, получено %q", t.Names)
+	}
+	return nil
+}
+
+// Format - адрес помещения, дома, участка или адресного объекта guid по шаблону t
+func (s *Store) Format(ctx context.Context, guid string, t Template) (string, error) {
+	if err := t.Validate(); err != nil {
+		return "", err
+	}
+	a, err := s.Resolve(ctx, guid, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return s.FormatAddress(ctx, a, t)
+}
+
+// FormatAddress - уже найденный адрес a по шаблону t
+func (s *Store) FormatAddress(ctx context.Context, a *Address, t Template) (string, error) {
+	full := t.Names == NamesFull
+	var parts []string
+	for _, o := range a.Objects {
+		if o.Level == 1 && !t.Region {
+			continue
+		}
+		name := o.FormalName
+		switch t.Names {
+		case NamesShort:
+			name = o.ShortName + " " + o.FormalName
+		case NamesFull:
+			typeName, err := s.typeName(ctx, o.Level, o.ShortName)
+			if err != nil {
+				return "", err
+			}
+			name = typeName + " " + o.FormalName
+		}
+		parts = append(parts, strings.TrimSpace(name))
+	}
+	if a.House != nil {
+		parts = append(parts, houseLabel(a.House, full))
+	}
+	if a.Stead != nil {
+		parts = append(parts, label(full, "уч", "участок")+" "+a.Stead.Number)
+	}
+	if a.Room != nil {
+		if a.Room.FlatNumber != "" {
+			parts = append(parts, label(full, "кв", "квартира")+" "+a.Room.FlatNumber)
+		}
+		if a.Room.RoomNumber != "" {
+			parts = append(parts, label(full, "ком", "комната")+" "+a.Room.RoomNumber)
+		}
+	}
+
+	if t.Order == OrderDesc {
+		for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+			parts[i], parts[j] = parts[j], parts[i]
+		}
+	}
+
+	if t.PostalCode {
+		if code := postalCode(a); code != "" {
+			if t.Order == OrderDesc {
+				parts = append(parts, code)
+			} else {
+				parts = append([]string{code}, parts...)
+			}
+		}
+	}
+
+	separator := t.Separator
+	if separator == "" {
+		separator = ", "
+	}
+	return strings.Join(parts, separator), nil
+}
+
+// typeName - полное наименование типа объекта shortname уровня level (SOCRNAME)
+func (s *Store) typeName(ctx context.Context, level int, shortname string) (string, error) {
+	var name string
+	err := s.DB.QueryRowContext(ctx, "SELECT socrname FROM address_object_type WHERE level = $1 AND scname = $2 LIMIT 1;", level, shortname).Scan(&name)
+	if err == sql.ErrNoRows {
+		return shortname, nil
+	}
+	return name, err
+}
+
+// postalCode - самый точный индекс адреса: помещение, дом, участок, затем объекты снизу вверх
+func postalCode(a *Address) string {
+	switch {
+	case a.Room != nil && a.Room.PostalCode.Valid:
+		return a.Room.PostalCode.String()
+	case a.House != nil && a.House.PostalCode.Valid:
+		return a.House.PostalCode.String()
+	case a.Stead != nil && a.Stead.PostalCode.Valid:
+		return a.Stead.PostalCode.String()
+	}
+	for i := len(a.Objects) - 1; i >= 0; i-- {
+		if a.Objects[i].PostalCode.Valid {
+			return strconv.FormatInt(a.Objects[i].PostalCode.Int64, 10)
+		}
+	}
+	return ""
+}
+
+// label - сокращение или полное слово
+func label(full bool, short, long string) string {
+	if full {
+		return long
+	}
+	return short
+}
+
+// houseLabel - номер дома с корпусом и строением
+func houseLabel(h *House, full bool) string {
+	if full {
+		return h.Label().Full()
+	}
+	return h.Label().String()
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+func TestTemplateValidate(t *testing.T) {
+	for name, tmpl := range Templates {
+		if err := tmpl.Validate(); err != nil {
+			t.Errorf("шаблон %s: %v", name, err)
+		}
+	}
+
+	invalid := []Template{
+		{Order: "up", Names: NamesShort},
+		{Order: "", Names: NamesShort},
+		{Order: OrderAsc, Names: "long"},
+		{Order: OrderDesc, Names: ""},
+	}
+	for _, tmpl := range invalid {
+		if err := tmpl.Validate(); err == nil {
+			t.Errorf("шаблон %+v без ошибки", tmpl)
+		}
+	}
+}
+
+func TestFormatAddress(t *testing.T) {
+	postal := func(code int64) model.NullInt {
+		return model.NullInt{NullInt64: sql.NullInt64{Int64: code, Valid: true}}
+	}
+	a := &Address{
+		Objects: []AddressObject{
+			{FormalName: "Москва", ShortName: "г", Level: 1},
+			{FormalName: "Ленина", ShortName: "ул", Level: 7, PostalCode: postal(101000)},
+		},
+		House: &House{HouseNum: "12", BuildNum: "1", EstStatus: 2, PostalCode: postal(101001)},
+		Room:  &Room{FlatNumber: "5"},
+	}
+	withSeparator := Templates["oneline"]
+	withSeparator.Names, withSeparator.Separator = NamesNone, " / "
+	withoutPostal := Templates["postal"]
+	withoutPostal.PostalCode, withoutPostal.Region = false, false
+
+	tests := []struct {
+		name string
+		t    Template
+		want string
+	}{
+		{"oneline", Templates["oneline"], "г Москва, ул Ленина, д 12 к 1, кв 5"},
+		{"postal", Templates["postal"], "кв 5, д 12 к 1, ул Ленина, г Москва, 101001"},
+		{"short", Templates["short"], "ул Ленина, д 12 к 1, кв 5"},
+		{"без типов, свой разделитель", withSeparator, "Москва / Ленина / д 12 к 1 / кв 5"},
+		{"postal без индекса и региона", withoutPostal, "кв 5, д 12 к 1, ул Ленина"},
+	}
+	s := New(nil)
+	for _, tt := range tests {
+		got, err := s.FormatAddress(context.Background(), a, tt.t)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: %q, ожидается %q", tt.name, got, tt.want)
+		}
+	}
+
+	// полные наименования типов из address_object_type, неизвестный тип остается сокращением
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	mock.ExpectQuery(`SELECT socrname FROM address_object_type`).WithArgs(1, "г").
+		WillReturnRows(sqlmock.NewRows([]string{"socrname"}).AddRow("Город"))
+	mock.ExpectQuery(`SELECT socrname FROM address_object_type`).WithArgs(7, "ул").
+		WillReturnRows(sqlmock.NewRows([]string{"socrname"}))
+
+	got, err := New(db).FormatAddress(context.Background(), a, Templates["official"])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "101001, Город Москва, ул Ленина, дом 12 корпус 1, квартира 5"; got != want {
+		t.Errorf("official: %q, ожидается %q", got, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
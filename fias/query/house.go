@@ -0,0 +1,73 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// FindHouses - дома объекта aoguid на дату at с номером label (без учета регистра);
+// признак владения проверяется, только если он указан в label
+func (s *Store) FindHouses(ctx context.Context, aoguid string, label model.HouseLabel, at time.Time) ([]House, error) {
+	query := "SELECT " + houseColumns + ` FROM house WHERE aoguid = $1 AND ` + valid + `
+	AND lower(coalesce(housenum, '')) = lower($3) AND lower(coalesce(buildnum, '')) = lower($4) AND lower(coalesce(strucnum, '')) = lower($5)`
+	args := []interface{}{aoguid, day(at), label.HouseNum, label.BuildNum, label.StrucNum}
+	if label.EstStatus != 0 {
+		query += " AND eststatus = $6"
+		args = append(args, label.EstStatus)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query+" ORDER BY houseguid;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var houses []House
+	for rows.Next() {
+		h, err := scanHouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		houses = append(houses, *h)
+	}
+	return houses, rows.Err()
+}
+
+// LoadHouseKinds - признаки владения и строения из справочников estate_status и structure_status
+// для вывода и разбора номеров домов (model.SetHouseKinds)
+func (s *Store) LoadHouseKinds(ctx context.Context) error {
+	estate, err := s.houseKinds(ctx, "SELECT eststatid, coalesce(shortname, ''), coalesce(name, '') FROM estate_status;")
+	if err != nil {
+		return fmt.Errorf("справочник estate_status: %w", err)
+	}
+	structure, err := s.houseKinds(ctx, "SELECT strstatid, coalesce(shortname, ''), coalesce(name, '') FROM structure_status;")
+	if err != nil {
+		return fmt.Errorf("справочник structure_status: %w", err)
+	}
+
+	model.SetHouseKinds(estate, structure)
+	return nil
+}
+
+// houseKinds - признаки из запроса query (код, сокращение, наименование)
+func (s *Store) houseKinds(ctx context.Context, query string) (map[int]model.HouseKind, error) {
+	rows, err := s.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	kinds := map[int]model.HouseKind{}
+	for rows.Next() {
+		var code int
+		var k model.HouseKind
+		if err := rows.Scan(&code, &k.Short, &k.Full); err != nil {
+			return nil, err
+		}
+		kinds[code] = k
+	}
+	return kinds, rows.Err()
+}
@@ -0,0 +1,92 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// Статусы интервала домов (справочник interval_status)
+const (
+	IntervalUndefined = 0
+	IntervalAll       = 1 // обычный - все номера
+	IntervalEven      = 2
+	IntervalOdd       = 3
+)
+
+// HouseInterval - интервал домов улицы с кодами (строка house_interval)
+type HouseInterval struct {
+	IntGUID    string        `json:"intguid"`
+	AOGUID     string        `json:"aoguid"`
+	Start      int           `json:"intstart"`
+	End        int           `json:"intend"`
+	Status     int           `json:"intstatus"`
+	PostalCode model.NullInt `json:"postalcode"`
+	OKATO      string        `json:"okato,omitempty"`
+	OKTMO      string        `json:"oktmo,omitempty"`
+	IFNSFL     string        `json:"ifnsfl,omitempty"`
+	IFNSUL     string        `json:"ifnsul,omitempty"`
+	StartDate  model.Date    `json:"startdate"`
+	EndDate    model.Date    `json:"enddate"`
+}
+
+// Contains - входит ли номер дома n в интервал с учетом четности
+func (i *HouseInterval) Contains(n int) bool {
+	if n < i.Start || n > i.End {
+		return false
+	}
+	switch i.Status {
+	case IntervalEven:
+		return n%2 == 0
+	case IntervalOdd:
+		return n%2 != 0
+	}
+	return true
+}
+
+// HouseNumber - числовая часть номера дома: "12а" -> 12, "12/1" -> 12
+func HouseNumber(housenum string) (int, bool) {
+	end := 0
+	for end < len(housenum) && housenum[end] >= '0' && housenum[end] <= '9' {
+		end++
+	}
+	n, err := strconv.Atoi(housenum[:end])
+	return n, err == nil
+}
+
+// IntervalFor - интервал улицы aoguid на дату at, в который входит номер дома housenum;
+// если номер попадает в несколько интервалов, возвращается самый узкий
+func (s *Store) IntervalFor(ctx context.Context, aoguid, housenum string, at time.Time) (*HouseInterval, error) {
+	n, ok := HouseNumber(housenum)
+	if !ok {
+		return nil, fmt.Errorf("номер дома %q не начинается с числа: %w", housenum, ErrNotFound)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, `SELECT intguid, aoguid, intstart, intend, intstatus, postalcode,
+	coalesce(okato, ''), coalesce(oktmo, ''), coalesce(ifnsfl, ''), coalesce(ifnsul, ''), startdate, enddate
+FROM house_interval WHERE aoguid = $1 AND `+valid+` AND intstart <= $3 AND intend >= $3
+ORDER BY intend - intstart;`, aoguid, day(at), n)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i HouseInterval
+		if err := rows.Scan(&i.IntGUID, &i.AOGUID, &i.Start, &i.End, &i.Status, &i.PostalCode,
+			&i.OKATO, &i.OKTMO, &i.IFNSFL, &i.IFNSUL, &i.StartDate, &i.EndDate); err != nil {
+			return nil, err
+		}
+		if i.Contains(n) {
+			return &i, nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return nil, fmt.Errorf("дом %s на %s: %w", housenum, aoguid, ErrNotFound)
+}
@@ -0,0 +1,51 @@
+package query
+
+import "testing"
+
+func TestHouseIntervalContains(t *testing.T) {
+	tests := []struct {
+		status int
+		n      int
+		want   bool
+	}{
+		{IntervalAll, 9, false},
+		{IntervalAll, 10, true},
+		{IntervalAll, 15, true},
+		{IntervalAll, 20, true},
+		{IntervalAll, 21, false},
+		{IntervalUndefined, 11, true},
+		{IntervalEven, 12, true},
+		{IntervalEven, 13, false},
+		{IntervalEven, 22, false},
+		{IntervalOdd, 13, true},
+		{IntervalOdd, 12, false},
+		{IntervalOdd, 9, false},
+	}
+	for _, tt := range tests {
+		i := HouseInterval{Start: 10, End: 20, Status: tt.status}
+		if got := i.Contains(tt.n); got != tt.want {
+			t.Errorf("интервал 10-20 статус %d: Contains(%d) = %v, ожидается %v", tt.status, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestHouseNumber(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+		ok   bool
+	}{
+		{"12", 12, true},
+		{"12а", 12, true},
+		{"7/1", 7, true},
+		{"3к2", 3, true},
+		{"", 0, false},
+		{"а12", 0, false},
+		{"/1", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := HouseNumber(tt.in); got != tt.want || ok != tt.ok {
+			t.Errorf("HouseNumber(%q) = %d, %v; ожидается %d, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
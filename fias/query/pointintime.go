@@ -0,0 +1,318 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+	"github.com/lib/pq"
+)
+
+// Address - адрес на дату: цепочка объектов от региона вниз и найденные дом, участок, помещение
+type Address struct {
+	Date    time.Time       `json:"date"`
+	Objects []AddressObject `json:"objects"`
+	House   *House          `json:"house,omitempty"`
+	Stead   *Stead          `json:"stead,omitempty"`
+	Room    *Room           `json:"room,omitempty"`
+	Text    string          `json:"text"`
+}
+
+// ObjectAt - версия адресного объекта aoguid, действовавшая на дату at
+func (s *Store) ObjectAt(ctx context.Context, aoguid string, at time.Time) (*AddressObject, error) {
+	row := s.DB.QueryRowContext(ctx, "SELECT "+objectColumns+" FROM address_objects WHERE aoguid = $1 AND "+valid+
+		" ORDER BY startdate DESC, updatedate DESC LIMIT 1;", aoguid, day(at))
+	return scanObject(row)
+}
+
+// objectByID - версия адресного объекта по AOID
+func (s *Store) objectByID(ctx context.Context, aoid string) (*AddressObject, error) {
+	row := s.DB.QueryRowContext(ctx, "SELECT "+objectColumns+" FROM address_objects WHERE aoid = $1;", aoid)
+	return scanObject(row)
+}
+
+// ObjectVersionAt - от версии aoid переходим по PREVID/NEXTID к версии, действовавшей на дату at
+func (s *Store) ObjectVersionAt(ctx context.Context, aoid string, at time.Time) (*AddressObject, error) {
+	at = day(at)
+	o, err := s.objectByID(ctx, aoid)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < maxDepth; i++ {
+		var next string
+		switch {
+		case o.StartDate.Valid && at.Before(o.StartDate.Time):
+			next = o.PrevID
+		case o.EndDate.Valid && !at.Before(o.EndDate.Time):
+			next = o.NextID
+		default:
+			return o, nil
+		}
+		if next == "" {
+			return nil, fmt.Errorf("%s на %s: %w", aoid, at.Format(time.DateOnly), ErrNotFound)
+		}
+		if o, err = s.objectByID(ctx, next); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%s: цепочка PREVID/NEXTID длиннее %d", aoid, maxDepth)
+}
+
+// AddressAt - полный адрес объекта aoguid на дату at (родители по PARENTGUID тоже на эту дату)
+func (s *Store) AddressAt(ctx context.Context, aoguid string, at time.Time) (*Address, error) {
+	objects, err := s.ancestorsAt(ctx, aoguid, at)
+	if err != nil {
+		return nil, err
+	}
+	a := &Address{Date: day(at), Objects: objects}
+	a.Text = a.String()
+	return a, nil
+}
+
+// ancestorsAt - объект aoguid и его родители на дату at, от региона вниз
+func (s *Store) ancestorsAt(ctx context.Context, aoguid string, at time.Time) ([]AddressObject, error) {
+	var chain []AddressObject
+	for guid := aoguid; guid != ""; {
+		if len(chain) == maxDepth {
+			return nil, fmt.Errorf("%s: иерархия глубже %d", aoguid, maxDepth)
+		}
+		o, err := s.ObjectAt(ctx, guid, at)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", guid, err)
+		}
+		chain = append(chain, *o)
+		guid = o.ParentGUID
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain, nil
+}
+
+// HouseAt - дом houseguid с адресом на дату at
+func (s *Store) HouseAt(ctx context.Context, houseguid string, at time.Time) (*Address, error) {
+	row := s.DB.QueryRowContext(ctx, "SELECT "+houseColumns+" FROM house WHERE houseguid = $1 AND "+valid+
+		" ORDER BY startdate DESC, updatedate DESC LIMIT 1;", houseguid, day(at))
+	h, err := scanHouse(row)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.AddressAt(ctx, h.AOGUID, at)
+	if err != nil {
+		return nil, err
+	}
+	a.House = h
+	a.Text = a.String()
+	return a, nil
+}
+
+// SteadAt - земельный участок steadguid с адресом на дату at
+func (s *Store) SteadAt(ctx context.Context, steadguid string, at time.Time) (*Address, error) {
+	row := s.DB.QueryRowContext(ctx, "SELECT "+steadColumns+" FROM steads WHERE steadguid = $1 AND "+valid+
+		" ORDER BY startdate DESC LIMIT 1;", steadguid, day(at))
+	st, err := scanStead(row)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.AddressAt(ctx, st.ParentGUID, at)
+	if err != nil {
+		return nil, err
+	}
+	a.Stead = st
+	a.Text = a.String()
+	return a, nil
+}
+
+// RoomAt - помещение roomguid с адресом дома на дату at
+func (s *Store) RoomAt(ctx context.Context, roomguid string, at time.Time) (*Address, error) {
+	row := s.DB.QueryRowContext(ctx, "SELECT "+roomColumns+" FROM rooms WHERE roomguid = $1 AND "+valid+
+		" ORDER BY startdate DESC LIMIT 1;", roomguid, day(at))
+	r, err := scanRoom(row)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := s.HouseAt(ctx, r.HouseGUID, at)
+	if err != nil {
+		return nil, err
+	}
+	a.Room = r
+	a.Text = a.String()
+	return a, nil
+}
+
+// Resolve - адрес на дату at по GUID помещения, дома, участка или адресного объекта
+func (s *Store) Resolve(ctx context.Context, guid string, at time.Time) (*Address, error) {
+	a, err := s.RoomAt(ctx, guid, at)
+	if errors.Is(err, ErrNotFound) {
+		a, err = s.HouseAt(ctx, guid, at)
+	}
+	if errors.Is(err, ErrNotFound) {
+		a, err = s.SteadAt(ctx, guid, at)
+	}
+	if errors.Is(err, ErrNotFound) {
+		a, err = s.AddressAt(ctx, guid, at)
+	}
+	return a, err
+}
+
+// FindAt - поиск адреса "регион, город, улица, дом" по наименованиям, действовавшим на дату at;
+// части разделяются запятыми, промежуточные уровни (район, округ) можно пропускать
+func (s *Store) FindAt(ctx context.Context, address string, at time.Time) ([]Address, error) {
+	var parts []string
+	for _, p := range strings.Split(address, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return nil, errors.New("пустой адрес")
+	}
+
+	// кандидаты: цепочки объектов, последний элемент - самый нижний найденный уровень
+	candidates := [][]AddressObject{nil}
+	for i, part := range parts {
+		var next [][]AddressObject
+		for _, chain := range candidates {
+			var parent *AddressObject
+			if len(chain) > 0 {
+				parent = &chain[len(chain)-1]
+			}
+			found, err := s.matchObject(ctx, part, parent, at)
+			if err != nil {
+				return nil, err
+			}
+			for _, o := range found {
+				next = append(next, append(append([]AddressObject{}, chain...), o))
+			}
+		}
+
+		if len(next) == 0 {
+			// последняя часть может быть номером дома
+			if i == len(parts)-1 && i > 0 {
+				return s.matchHouses(ctx, part, candidates, at)
+			}
+			return nil, fmt.Errorf("%q: %w", part, ErrNotFound)
+		}
+		candidates = next
+	}
+
+	var result []Address
+	for _, chain := range candidates {
+		a, err := s.AddressAt(ctx, chain[len(chain)-1].AOGUID, at)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *a)
+	}
+	return result, nil
+}
+
+// matchObject - объекты с наименованием part (с типом или без) на дату at, потомки parent
+func (s *Store) matchObject(ctx context.Context, part string, parent *AddressObject, at time.Time) ([]AddressObject, error) {
+	names := []string{part}
+	if words := strings.Fields(part); len(words) > 1 {
+		// "ул Ленина", "Ленина ул", "г. Казань"
+		names = append(names, strings.Join(words[1:], " "), strings.Join(words[:len(words)-1], " "))
+	}
+
+	for i := range names {
+		names[i] = strings.ToLower(names[i])
+	}
+
+	query := "SELECT " + objectColumns + " FROM address_objects WHERE lower(formalname) = ANY($1) AND " + valid + ";"
+	args := []interface{}{pq.Array(names), day(at)}
+	if parent != nil {
+		query, args = descendantsQuery, append(args, parent.AOGUID, maxDepth)
+	}
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var found []AddressObject
+	for rows.Next() {
+		o, err := scanObject(rows)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, *o)
+	}
+	return found, rows.Err()
+}
+
+// descendantsQuery - объекты с наименованием из $1 на дату $2, потомки $3: от каждого найденного
+// объекта поднимаемся по PARENTGUID (не глубже $4), пока не встретим $3
+const descendantsQuery = `WITH RECURSIVE found AS (
+	SELECT * FROM address_objects WHERE lower(formalname) = ANY($1) AND ` + valid + `
+), chain AS (
+	SELECT aoid, coalesce(parentguid, '') AS guid, 1 AS depth FROM found
+	UNION ALL
+	SELECT c.aoid, coalesce(p.parentguid, ''), c.depth + 1 FROM chain c
+	JOIN LATERAL (SELECT parentguid FROM address_objects WHERE aoguid = c.guid AND ` + valid + `
+		ORDER BY startdate DESC, updatedate DESC LIMIT 1) p ON true
+	WHERE c.guid <> $3 AND c.depth < $4
+)
+SELECT ` + objectColumns + ` FROM found WHERE aoid IN (SELECT aoid FROM chain WHERE guid = $3);`
+
+// matchHouses - дома с номером part у последних объектов цепочек candidates
+func (s *Store) matchHouses(ctx context.Context, part string, candidates [][]AddressObject, at time.Time) ([]Address, error) {
+	label, err := model.ParseHouseLabel(part)
+	if err != nil {
+		return nil, fmt.Errorf("%q: %w", part, ErrNotFound)
+	}
+
+	var result []Address
+	for _, chain := range candidates {
+		houses, err := s.FindHouses(ctx, chain[len(chain)-1].AOGUID, label, at)
+		if err != nil {
+			return nil, err
+		}
+		for i := range houses {
+			a, err := s.AddressAt(ctx, houses[i].AOGUID, at)
+			if err != nil {
+				return nil, err
+			}
+			a.House = &houses[i]
+			a.Text = a.String()
+			result = append(result, *a)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("%q: %w", part, ErrNotFound)
+	}
+	return result, nil
+}
+
+// String - адрес одной строкой: "г Москва, ул Тверская, д 7"
+func (a *Address) String() string {
+	var parts []string
+	for _, o := range a.Objects {
+		parts = append(parts, strings.TrimSpace(o.ShortName+" "+o.FormalName))
+	}
+	if a.House != nil {
+		parts = append(parts, houseLabel(a.House, false))
+	}
+	if a.Stead != nil {
+		parts = append(parts, "уч "+a.Stead.Number)
+	}
+	if a.Room != nil {
+		if a.Room.FlatNumber != "" {
+			parts = append(parts, "кв "+a.Room.FlatNumber)
+		}
+		if a.Room.RoomNumber != "" {
+			parts = append(parts, "ком "+a.Room.RoomNumber)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,41 @@
+package query
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/lib/pq"
+)
+
+// TestMatchObject - потомки parent отбираются одним запросом, а не проверкой родителей каждого найденного объекта
+func TestMatchObject(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	at := time.Date(2020, 5, 14, 10, 0, 0, 0, time.UTC)
+	names := pq.Array([]string{"ул ленина", "ленина", "ул"})
+	mock.ExpectQuery(`^SELECT .+ FROM address_objects WHERE lower\(formalname\) = ANY\(\$1\)`).
+		WithArgs(names, day(at)).
+		WillReturnRows(objectRow(sqlmock.NewRows(objectNames), "a", "Ленина", nil))
+	mock.ExpectQuery(`^WITH RECURSIVE found AS .+ FROM found WHERE aoid IN \(SELECT aoid FROM chain WHERE guid = \$3\);$`).
+		WithArgs(names, day(at), "город", maxDepth).
+		WillReturnRows(objectRow(objectRow(sqlmock.NewRows(objectNames), "b", "Ленина", nil), "c", "Ленина", nil))
+
+	s := New(db)
+	found, err := s.matchObject(context.Background(), "ул Ленина", nil, at)
+	if err != nil || len(found) != 1 {
+		t.Fatalf("найдено %v, %v", found, err)
+	}
+	found, err = s.matchObject(context.Background(), "ул Ленина", &AddressObject{AOGUID: "город"}, at)
+	if err != nil || len(found) != 2 || found[0].AOGUID != "b" || found[1].AOGUID != "c" {
+		t.Fatalf("найдено %v, %v", found, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidPostalCode - строка не является почтовым индексом
+var ErrInvalidPostalCode = errors.New("неверный почтовый индекс")
+
+// PostalCode - почтовый индекс объекта; Inherited - индекс взят у родителя, Source - GUID владельца индекса
+type PostalCode struct {
+	PostalCode int64  `json:"postalcode"`
+	GUID       string `json:"guid"`
+	Source     string `json:"source"`
+	Inherited  bool   `json:"inherited"`
+}
+
+// PostalArea - улицы и дома с почтовым индексом
+type PostalArea struct {
+	PostalCode int64           `json:"postalcode"`
+	Objects    []AddressObject `json:"objects"`
+	Houses     []House         `json:"houses"`
+}
+
+// ParsePostalCode - индекс из ровно 6 цифр 0-9; знак, пробелы и цифры других
+// алфавитов, которые пропустил бы strconv.ParseInt или unicode.IsDigit, не допускаются
+func ParsePostalCode(s string) (int64, error) {
+	if len(s) != 6 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidPostalCode, s)
+	}
+	var code int64
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return 0, fmt.Errorf("%w: %q", ErrInvalidPostalCode, s)
+		}
+		code = code*10 + int64(s[i]-'0')
+	}
+	return code, nil
+}
+
+// PostalCodeOf - действующий почтовый индекс дома или адресного объекта guid;
+// если у записи индекс не заполнен, берется индекс ближайшего родителя
+func (s *Store) PostalCodeOf(ctx context.Context, guid string) (*PostalCode, error) {
+	now := time.Now()
+	aoguid := guid
+
+	a, err := s.HouseAt(ctx, guid, now)
+	switch {
+	case err == nil:
+		if a.House.PostalCode.Valid {
+			return &PostalCode{PostalCode: a.House.PostalCode.Int64, GUID: guid, Source: guid}, nil
+		}
+		aoguid = a.House.AOGUID
+	case !errors.Is(err, ErrNotFound):
+		return nil, err
+	}
+
+	chain, err := s.ancestorsAt(ctx, aoguid, now)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(chain) - 1; i >= 0; i-- {
+		if o := chain[i]; o.PostalCode.Valid {
+			return &PostalCode{PostalCode: o.PostalCode.Int64, GUID: guid, Source: o.AOGUID, Inherited: o.AOGUID != guid}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("почтовый индекс %s: %w", guid, ErrNotFound)
+}
+
+// ByPostalCode - действующие адресные объекты и дома с индексом code; если пересчитана
+// производная таблица postal_codes, учитываются и индексы, унаследованные от родителей
+func (s *Store) ByPostalCode(ctx context.Context, code int64) (*PostalArea, error) {
+	var indexed sql.NullString
+	if err := s.DB.QueryRowContext(ctx, "SELECT to_regclass('postal_codes')::text;").Scan(&indexed); err != nil {
+		return nil, err
+	}
+
+	objectQuery := "SELECT " + objectColumns + " FROM address_objects WHERE postalcode = $1 AND " + valid + " ORDER BY aolevel, formalname;"
+	houseQuery := "SELECT " + houseColumns + " FROM house WHERE postalcode = $1 AND " + valid + " ORDER BY aoguid, housenum;"
+	if indexed.Valid {
+		objectQuery = "SELECT " + objectColumns + " FROM address_objects WHERE aoguid IN (SELECT guid FROM postal_codes WHERE postalcode = $1 AND kind = 'object') AND " + valid + " ORDER BY aolevel, formalname;"
+		houseQuery = "SELECT " + houseColumns + " FROM house WHERE houseguid IN (SELECT guid FROM postal_codes WHERE postalcode = $1 AND kind = 'house') AND " + valid + " ORDER BY aoguid, housenum;"
+	}
+
+	area := &PostalArea{PostalCode: code}
+	now := day(time.Now())
+
+	rows, err := s.DB.QueryContext(ctx, objectQuery, code, now)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		o, err := scanObject(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		area.Objects = append(area.Objects, *o)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rows, err = s.DB.QueryContext(ctx, houseQuery, code, now)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		h, err := scanHouse(rows)
+		if err != nil {
+			return nil, err
+		}
+		area.Houses = append(area.Houses, *h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(area.Objects) == 0 && len(area.Houses) == 0 {
+		return nil, fmt.Errorf("индекс %d: %w", code, ErrNotFound)
+	}
+	return area, nil
+}
@@ -0,0 +1,98 @@
+package query
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// objectRow - строка address_objects в порядке objectColumns
+func objectRow(rows *sqlmock.Rows, aoguid, name string, postalcode interface{}) *sqlmock.Rows {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	return rows.AddRow("id-"+aoguid, aoguid, "", name, name, "ул", 7, "77", postalcode, "", "", "", "",
+		"", "", 1, 1, "", start, start, time.Date(2079, 6, 6, 0, 0, 0, 0, time.UTC))
+}
+
+// houseRow - строка house в порядке houseColumns
+func houseRow(rows *sqlmock.Rows, houseguid, aoguid, number string, postalcode interface{}) *sqlmock.Rows {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	return rows.AddRow("id-"+houseguid, houseguid, aoguid, number, "", "", 2, 0, postalcode, "", "", "", "",
+		"", "", start, start, time.Date(2079, 6, 6, 0, 0, 0, 0, time.UTC))
+}
+
+var (
+	objectNames = []string{"aoid", "aoguid", "parentguid", "formalname", "offname", "shortname", "aolevel",
+		"regioncode", "postalcode", "okato", "oktmo", "ifnsfl", "ifnsul",
+		"previd", "nextid", "actstatus", "operstatus", "normdoc", "updatedate", "startdate", "enddate"}
+	houseNames = []string{"houseid", "houseguid", "aoguid", "housenum", "buildnum", "strucnum",
+		"eststatus", "strstatus", "postalcode", "okato", "oktmo", "ifnsfl", "ifnsul",
+		"cadnum", "normdoc", "updatedate", "startdate", "enddate"}
+)
+
+func TestByPostalCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		indexed bool
+		objects int
+		houses  int
+		source  string // откуда выбираются записи
+	}{
+		{"по колонке postalcode", false, 1, 2, `FROM address_objects WHERE postalcode = \$1`},
+		{"с унаследованными индексами", true, 1, 2, `FROM postal_codes WHERE postalcode = \$1 AND kind = 'object'`},
+		{"нет записей", false, 0, 0, `FROM address_objects WHERE postalcode = \$1`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+
+			var indexed interface{}
+			if tt.indexed {
+				indexed = "postal_codes"
+			}
+			mock.ExpectQuery(`SELECT to_regclass\('postal_codes'\)`).WillReturnRows(sqlmock.NewRows([]string{"to_regclass"}).AddRow(indexed))
+			objects := sqlmock.NewRows(objectNames)
+			for i := 0; i < tt.objects; i++ {
+				objectRow(objects, "street", "Тверская", 125009)
+			}
+			mock.ExpectQuery(tt.source).WithArgs(int64(125009), sqlmock.AnyArg()).WillReturnRows(objects)
+			houses := sqlmock.NewRows(houseNames)
+			for i := 0; i < tt.houses; i++ {
+				// у домов кроме первого индекс унаследован от улицы
+				var postalcode interface{}
+				if i == 0 {
+					postalcode = 125009
+				}
+				houseRow(houses, "house", "street", "1", postalcode)
+			}
+			mock.ExpectQuery(`FROM house WHERE`).WithArgs(int64(125009), sqlmock.AnyArg()).WillReturnRows(houses)
+
+			area, err := New(db).ByPostalCode(context.Background(), 125009)
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Error(err)
+			}
+			if tt.objects == 0 && tt.houses == 0 {
+				if !errors.Is(err, ErrNotFound) {
+					t.Fatalf("ошибка %v, ожидается ErrNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if area.PostalCode != 125009 || len(area.Objects) != tt.objects || len(area.Houses) != tt.houses {
+				t.Errorf("индекс %d, объектов %d, домов %d", area.PostalCode, len(area.Objects), len(area.Houses))
+			}
+			if area.Objects[0].FormalName != "Тверская" || area.Houses[1].PostalCode.Valid {
+				t.Errorf("объект %+v, дом %+v", area.Objects[0], area.Houses[1])
+			}
+		})
+	}
+}
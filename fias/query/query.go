@@ -0,0 +1,32 @@
+// Package query - запросы к загруженному в PostgreSQL адресному реестру ФИАС
+package query
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotFound - запись не найдена
+var ErrNotFound = errors.New("запись не найдена")
+
+// maxDepth - ограничение обхода иерархии и цепочек PREVID/NEXTID на случай циклов в данных
+const maxDepth = 64
+
+// Store - запросы к БД с загруженным ФИАС
+type Store struct {
+	DB *sql.DB
+}
+
+// New - запросы к БД db
+func New(db *sql.DB) *Store {
+	return &Store{DB: db}
+}
+
+// day - дата без времени для сравнения с колонками date
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// valid - условие действия записи на дату $2 (STARTDATE включительно, ENDDATE не включительно)
+const valid = "startdate <= $2 AND (enddate IS NULL OR enddate > $2)"
@@ -0,0 +1,81 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeCadastral(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"77:01:0001001:1234", "77:01:0001001:1234"},
+		{"7:1:0001001:12", "07:01:0001001:12"},
+		{" 77 : 01 : 0001001 : 1234 ", "77:01:0001001:1234"},
+		{"77:01:0001001:1234\u00a0", "77:01:0001001:1234"},
+		{"77.01.0001001.1234", "77:01:0001001:1234"},
+		{"77;01;000100;5", "77:01:000100:5"},
+		{"77\uff1a01\uff1a0001001\uff1a1234", "77:01:0001001:1234"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeCadastral(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeCadastral(%q) = %q, %v; ожидается %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"", "77:01", "77:01:01:1", "777:01:0001001:1", "77:01:0001001:", "кадастр 77:01:0001001:1",
+		"77 01:0001001:1234", // пробелы удаляются, округ и район без разделителя не разобрать
+	}
+	for _, in := range invalid {
+		if got, err := NormalizeCadastral(in); !errors.Is(err, ErrInvalidCadastral) {
+			t.Errorf("NormalizeCadastral(%q) = %q, %v; ожидается ErrInvalidCadastral", in, got, err)
+		}
+	}
+}
+
+func TestParseRoomParts(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []RoomPart
+	}{
+		{"кв 12", []RoomPart{{"кв", "12"}}},
+		{"кв. 12, ком. 3", []RoomPart{{"кв", "12"}, {"ком", "3"}}},
+		{"Кв.12 Ком.3а", []RoomPart{{"кв", "12"}, {"ком", "3а"}}},
+		{"ком 3", []RoomPart{{"ком", "3"}}},
+		{"12", []RoomPart{{"", "12"}}},
+		{"12 3", []RoomPart{{"", "12"}, {"", "3"}}},
+		{"оф 5-1", []RoomPart{{"оф", "5-1"}}},
+		{"пом. 7/2", []RoomPart{{"пом", "7/2"}}},
+		{"квартира 15", []RoomPart{{"квартира", "15"}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseRoomParts(tt.in)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseRoomParts(%q) = %v, %v; ожидается %v", tt.in, got, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{"", "кв", "квартира без номера", "кв 1 ком 2 оф 3"} {
+		if got, err := ParseRoomParts(in); !errors.Is(err, ErrInvalidRoom) {
+			t.Errorf("ParseRoomParts(%q) = %v, %v; ожидается ErrInvalidRoom", in, got, err)
+		}
+	}
+}
+
+func TestParsePostalCode(t *testing.T) {
+	for in, want := range map[string]int64{"101000": 101000, "000001": 1, "630099": 630099} {
+		if got, err := ParsePostalCode(in); err != nil || got != want {
+			t.Errorf("ParsePostalCode(%q) = %d, %v; ожидается %d", in, got, err, want)
+		}
+	}
+
+	invalid := []string{"", "12345", "1234567", "+12345", "-12345", " 12345", "12345 ", "12a456", "1_2345", "١٢٣٤٥٦"}
+	for _, in := range invalid {
+		if got, err := ParsePostalCode(in); !errors.Is(err, ErrInvalidPostalCode) {
+			t.Errorf("ParsePostalCode(%q) = %d, %v; ожидается ErrInvalidPostalCode", in, got, err)
+		}
+	}
+}
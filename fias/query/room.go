@@ -0,0 +1,144 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// roomPartPattern - тип (слово) и номер: "кв 12", "ком.3", "оф 5а"
+var roomPartPattern = regexp.MustCompile(`([\p{L}][\p{L}.\-]*)?\s*([0-9][0-9\p{L}/\-]*)`)
+
+// ErrInvalidRoom - строка не похожа на номер помещения
+var ErrInvalidRoom = errors.New("неверный номер помещения")
+
+// RoomPart - часть номера помещения: тип и номер
+type RoomPart struct {
+	Type   string `json:"type,omitempty"`
+	Number string `json:"number"`
+}
+
+// ParseRoomParts - "кв 12 ком 3" -> [{кв 12} {ком 3}]; квартира это или комната, определяет
+// тип (FindRooms), части без типа - по порядку: сначала квартира, затем комната
+func ParseRoomParts(s string) ([]RoomPart, error) {
+	var parts []RoomPart
+	for _, m := range roomPartPattern.FindAllStringSubmatch(strings.ToLower(s), -1) {
+		parts = append(parts, RoomPart{Type: strings.Trim(m[1], ".-"), Number: m[2]})
+	}
+	if len(parts) == 0 || len(parts) > 2 {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidRoom, s)
+	}
+	return parts, nil
+}
+
+// roomFilter - условие на квартиру или комнату: номер и код типа, если тип указан
+type roomFilter struct {
+	number  string
+	typeID  int
+	hasType bool
+}
+
+// FindRooms - помещения дома houseguid на дату at по строке вида "кв 12 ком 3", "ком 3";
+// тип ищется в справочниках flat_type и room_type (сокращение или наименование): тип из
+// room_type без квартиры - комната в помещении без номера
+func (s *Store) FindRooms(ctx context.Context, houseguid, text string, at time.Time) ([]Room, error) {
+	parts, err := ParseRoomParts(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var flat, room *roomFilter
+	for i, part := range parts {
+		isRoom := i == 1
+		filter := &roomFilter{number: part.Number}
+		if part.Type != "" {
+			flatID, isFlatType, err := s.typeID(ctx, "flat_type", "fltypeid", part.Type)
+			if err != nil {
+				return nil, err
+			}
+			roomID, isRoomType, err := s.typeID(ctx, "room_type", "rmtypeid", part.Type)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case !isFlatType && !isRoomType:
+				return nil, fmt.Errorf("%w: неизвестный тип %q", ErrInvalidRoom, part.Type)
+			case !isFlatType:
+				isRoom = true
+			case !isRoomType:
+				isRoom = false
+			}
+			filter.typeID, filter.hasType = flatID, true
+			if isRoom {
+				filter.typeID = roomID
+			}
+		}
+
+		dst := &flat
+		if isRoom {
+			dst = &room
+		}
+		if *dst != nil {
+			return nil, fmt.Errorf("%w: %q: квартира или комната указана дважды", ErrInvalidRoom, text)
+		}
+		*dst = filter
+	}
+
+	query := "SELECT " + roomColumns + " FROM rooms WHERE houseguid = $1 AND " + valid
+	args := []interface{}{houseguid, day(at)}
+	for _, c := range []struct {
+		filter             *roomFilter
+		number, typeColumn string
+	}{
+		{flat, "flatnumber", "flattype"},
+		{room, "roomnumber", "roomtype"},
+	} {
+		if c.filter == nil {
+			query += fmt.Sprintf(" AND coalesce(%s, '') = ''", c.number)
+			continue
+		}
+		args = append(args, c.filter.number)
+		query += fmt.Sprintf(" AND lower(%s) = $%d", c.number, len(args))
+		if c.filter.hasType {
+			args = append(args, c.filter.typeID)
+			query += fmt.Sprintf(" AND %s = $%d", c.typeColumn, len(args))
+		}
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query+" ORDER BY roomguid;", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rooms []Room
+	for rows.Next() {
+		r, err := scanRoom(rows)
+		if err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, *r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(rooms) == 0 {
+		return nil, fmt.Errorf("%q в доме %s: %w", text, houseguid, ErrNotFound)
+	}
+	return rooms, nil
+}
+
+// typeID - код типа из справочника table по сокращению ("кв", "кв.") или наименованию
+// ("квартира"); ok = false, если такого типа нет
+func (s *Store) typeID(ctx context.Context, table, column, word string) (id int, ok bool, err error) {
+	err = s.DB.QueryRowContext(ctx, fmt.Sprintf(`SELECT %s FROM %s
+WHERE lower(trim(trailing '.' from shortname)) = $1 OR lower(name) = $1 ORDER BY %s LIMIT 1;`, column, table, column), word).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	return id, err == nil, err
+}
@@ -0,0 +1,123 @@
+package query
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// Operation - статус действия (справочник operation_status)
+type Operation struct {
+	Code int    `json:"code"`
+	Name string `json:"name"`
+}
+
+// NormativeDocument - нормативный документ, на основании которого изменен объект
+type NormativeDocument struct {
+	ID       string     `json:"normdocid"`
+	Name     string     `json:"docname,omitempty"`
+	Number   string     `json:"docnum,omitempty"`
+	Date     model.Date `json:"docdate"`
+	Type     int        `json:"doctype"`
+	TypeName string     `json:"doctype_name,omitempty"`
+}
+
+// TimelineEntry - версия адресного объекта в истории изменений
+type TimelineEntry struct {
+	Object    AddressObject      `json:"object"`
+	Name      string             `json:"name"` // тип и наименование: "ул Ленина"
+	Operation Operation          `json:"operation"`
+	Document  *NormativeDocument `json:"document,omitempty"`
+	Changed   []string           `json:"changed,omitempty"` // поля, изменившиеся относительно предыдущей версии
+}
+
+// Timeline - история адресного объекта aoguid: наименования по периодам действия,
+// статус действия (переименование, слияние, переподчинение...) и нормативный документ
+func (s *Store) Timeline(ctx context.Context, aoguid string) ([]TimelineEntry, error) {
+	rows, err := s.DB.QueryContext(ctx, "SELECT "+objectColumns+" FROM address_objects WHERE aoguid = $1 ORDER BY startdate, updatedate;", aoguid)
+	if err != nil {
+		return nil, err
+	}
+
+	var timeline []TimelineEntry
+	for rows.Next() {
+		o, err := scanObject(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		timeline = append(timeline, TimelineEntry{Object: *o, Name: o.ShortName + " " + o.FormalName})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(timeline) == 0 {
+		return nil, fmt.Errorf("%s: %w", aoguid, ErrNotFound)
+	}
+
+	for i := range timeline {
+		e := &timeline[i]
+		if e.Operation, err = s.operation(ctx, e.Object.OperStatus); err != nil {
+			return nil, err
+		}
+		if e.Object.NormDoc != "" {
+			if e.Document, err = s.normativeDocument(ctx, e.Object.NormDoc); err != nil {
+				return nil, err
+			}
+		}
+		if i > 0 {
+			e.Changed = changedFields(&timeline[i-1].Object, &e.Object)
+		}
+	}
+
+	return timeline, nil
+}
+
+// operation - наименование статуса действия code
+func (s *Store) operation(ctx context.Context, code int) (Operation, error) {
+	op := Operation{Code: code}
+	err := s.DB.QueryRowContext(ctx, "SELECT name FROM operation_status WHERE operstatid = $1;", code).Scan(&op.Name)
+	if err == sql.ErrNoRows {
+		return op, nil
+	}
+	return op, err
+}
+
+// normativeDocument - документ id с наименованием типа, nil если документа нет в справочнике
+func (s *Store) normativeDocument(ctx context.Context, id string) (*NormativeDocument, error) {
+	d := NormativeDocument{ID: id}
+	err := s.DB.QueryRowContext(ctx, `SELECT coalesce(d.docname, ''), coalesce(d.docnum, ''), d.docdate, d.doctype, coalesce(t.name, '')
+FROM normative_document d LEFT JOIN normative_document_type t ON t.ndtypeid = d.doctype
+WHERE d.normdocid = $1;`, id).Scan(&d.Name, &d.Number, &d.Date, &d.Type, &d.TypeName)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// changedFields - поля, которые отличаются в версии cur от предыдущей prev
+func changedFields(prev, cur *AddressObject) []string {
+	var changed []string
+	check := func(name string, a, b interface{}) {
+		if a != b {
+			changed = append(changed, name)
+		}
+	}
+	check("formalname", prev.FormalName, cur.FormalName)
+	check("offname", prev.OffName, cur.OffName)
+	check("shortname", prev.ShortName, cur.ShortName)
+	check("parentguid", prev.ParentGUID, cur.ParentGUID)
+	check("aolevel", prev.Level, cur.Level)
+	check("postalcode", prev.PostalCode.String(), cur.PostalCode.String())
+	check("okato", prev.OKATO, cur.OKATO)
+	check("oktmo", prev.OKTMO, cur.OKTMO)
+	check("ifnsfl", prev.IFNSFL, cur.IFNSFL)
+	check("ifnsul", prev.IFNSUL, cur.IFNSUL)
+	return changed
+}
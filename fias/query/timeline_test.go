@@ -0,0 +1,43 @@
+package query
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+func TestChangedFields(t *testing.T) {
+	base := AddressObject{
+		AOID: "1", AOGUID: "g", ParentGUID: "p", FormalName: "Ленина", ShortName: "ул", Level: 7,
+		OKATO: "45286560000", OKTMO: "45380000",
+	}
+	postal := model.NullInt{NullInt64: sql.NullInt64{Int64: 101000, Valid: true}}
+
+	tests := []struct {
+		name   string
+		change func(o *AddressObject)
+		want   []string
+	}{
+		{"без изменений", func(o *AddressObject) {}, nil},
+		{"новая версия записи", func(o *AddressObject) { o.AOID, o.PrevID = "2", "1" }, nil},
+		{"переименование", func(o *AddressObject) { o.FormalName = "Ленинский" }, []string{"formalname"}},
+		{"индекс появился", func(o *AddressObject) { o.PostalCode = postal }, []string{"postalcode"}},
+		{"переподчинение", func(o *AddressObject) { o.ParentGUID, o.Level = "q", 65 }, []string{"parentguid", "aolevel"}},
+	}
+	for _, tt := range tests {
+		prev, cur := base, base
+		tt.change(&cur)
+		if got := changedFields(&prev, &cur); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: changedFields = %v, ожидается %v", tt.name, got, tt.want)
+		}
+	}
+
+	// индекс пропал
+	prev, cur := base, base
+	prev.PostalCode = postal
+	if got := changedFields(&prev, &cur); !reflect.DeepEqual(got, []string{"postalcode"}) {
+		t.Errorf("changedFields = %v, ожидается [postalcode]", got)
+	}
+}
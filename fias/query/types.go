@@ -0,0 +1,167 @@
+package query
+
+import (
+	"database/sql"
+
+	"github.com/SliderVM/FIASParse/fias/model"
+)
+
+// AddressObject - версия адресного объекта (строка address_objects)
+type AddressObject struct {
+	AOID       string        `json:"aoid"`
+	AOGUID     string        `json:"aoguid"`
+	ParentGUID string        `json:"parentguid,omitempty"`
+	FormalName string        `json:"formalname"`
+	OffName    string        `json:"offname,omitempty"`
+	ShortName  string        `json:"shortname"`
+	Level      int           `json:"aolevel"`
+	RegionCode string        `json:"regioncode"`
+	PostalCode model.NullInt `json:"postalcode"`
+	OKATO      string        `json:"okato,omitempty"`
+	OKTMO      string        `json:"oktmo,omitempty"`
+	IFNSFL     string        `json:"ifnsfl,omitempty"`
+	IFNSUL     string        `json:"ifnsul,omitempty"`
+	PrevID     string        `json:"previd,omitempty"`
+	NextID     string        `json:"nextid,omitempty"`
+	ActStatus  int           `json:"actstatus"`
+	OperStatus int           `json:"operstatus"`
+	NormDoc    string        `json:"normdoc,omitempty"`
+	UpdateDate model.Date    `json:"updatedate"`
+	StartDate  model.Date    `json:"startdate"`
+	EndDate    model.Date    `json:"enddate"`
+}
+
+const objectColumns = `aoid, aoguid, coalesce(parentguid, ''), formalname, coalesce(offname, ''), shortname, aolevel,
+	coalesce(regioncode, ''), postalcode, coalesce(okato, ''), coalesce(oktmo, ''), coalesce(ifnsfl, ''), coalesce(ifnsul, ''),
+	coalesce(previd, ''), coalesce(nextid, ''), actstatus, operstatus, coalesce(normdoc, ''), updatedate, startdate, enddate`
+
+func scanObject(row interface{ Scan(...interface{}) error }) (*AddressObject, error) {
+	var o AddressObject
+	err := row.Scan(&o.AOID, &o.AOGUID, &o.ParentGUID, &o.FormalName, &o.OffName, &o.ShortName, &o.Level,
+		&o.RegionCode, &o.PostalCode, &o.OKATO, &o.OKTMO, &o.IFNSFL, &o.IFNSUL,
+		&o.PrevID, &o.NextID, &o.ActStatus, &o.OperStatus, &o.NormDoc, &o.UpdateDate, &o.StartDate, &o.EndDate)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &o, nil
+}
+
+// House - версия дома (строка house)
+type House struct {
+	HouseID    string        `json:"houseid"`
+	HouseGUID  string        `json:"houseguid"`
+	AOGUID     string        `json:"aoguid"`
+	HouseNum   string        `json:"housenum,omitempty"`
+	BuildNum   string        `json:"buildnum,omitempty"`
+	StrucNum   string        `json:"strucnum,omitempty"`
+	EstStatus  int           `json:"eststatus"`
+	StrStatus  int           `json:"strstatus"`
+	PostalCode model.NullInt `json:"postalcode"`
+	OKATO      string        `json:"okato,omitempty"`
+	OKTMO      string        `json:"oktmo,omitempty"`
+	IFNSFL     string        `json:"ifnsfl,omitempty"`
+	IFNSUL     string        `json:"ifnsul,omitempty"`
+	CadNum     string        `json:"cadnum,omitempty"`
+	NormDoc    string        `json:"normdoc,omitempty"`
+	UpdateDate model.Date    `json:"updatedate"`
+	StartDate  model.Date    `json:"startdate"`
+	EndDate    model.Date    `json:"enddate"`
+}
+
+// Label - номер дома с признаками владения и строения
+func (h *House) Label() model.HouseLabel {
+	return model.HouseLabel{EstStatus: h.EstStatus, HouseNum: h.HouseNum, BuildNum: h.BuildNum, StrStatus: h.StrStatus, StrucNum: h.StrucNum}
+}
+
+const houseColumns = `houseid, houseguid, aoguid, coalesce(housenum, ''), coalesce(buildnum, ''), coalesce(strucnum, ''),
+	eststatus, strstatus, postalcode, coalesce(okato, ''), coalesce(oktmo, ''), coalesce(ifnsfl, ''), coalesce(ifnsul, ''),
+	coalesce(cadnum, ''), coalesce(normdoc, ''), updatedate, startdate, enddate`
+
+func scanHouse(row interface{ Scan(...interface{}) error }) (*House, error) {
+	var h House
+	err := row.Scan(&h.HouseID, &h.HouseGUID, &h.AOGUID, &h.HouseNum, &h.BuildNum, &h.StrucNum,
+		&h.EstStatus, &h.StrStatus, &h.PostalCode, &h.OKATO, &h.OKTMO, &h.IFNSFL, &h.IFNSUL,
+		&h.CadNum, &h.NormDoc, &h.UpdateDate, &h.StartDate, &h.EndDate)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Stead - версия земельного участка (строка steads)
+type Stead struct {
+	SteadID    string        `json:"steadid"`
+	SteadGUID  string        `json:"steadguid"`
+	ParentGUID string        `json:"parentguid"`
+	Number     string        `json:"number"`
+	PostalCode model.NullInt `json:"postalcode"`
+	OKATO      string        `json:"okato,omitempty"`
+	OKTMO      string        `json:"oktmo,omitempty"`
+	IFNSFL     string        `json:"ifnsfl,omitempty"`
+	IFNSUL     string        `json:"ifnsul,omitempty"`
+	CadNum     string        `json:"cadnum,omitempty"`
+	PrevID     string        `json:"previd,omitempty"`
+	NextID     string        `json:"nextid,omitempty"`
+	OperStatus int           `json:"operstatus"`
+	StartDate  model.Date    `json:"startdate"`
+	EndDate    model.Date    `json:"enddate"`
+}
+
+const steadColumns = `steadid, steadguid, coalesce(parentguid, ''), coalesce(number, ''), postalcode,
+	coalesce(okato, ''), coalesce(oktmo, ''), coalesce(ifnsfl, ''), coalesce(ifnsul, ''), coalesce(cadnum, ''),
+	coalesce(previd, ''), coalesce(nextid, ''), operstatus, startdate, enddate`
+
+func scanStead(row interface{ Scan(...interface{}) error }) (*Stead, error) {
+	var s Stead
+	err := row.Scan(&s.SteadID, &s.SteadGUID, &s.ParentGUID, &s.Number, &s.PostalCode,
+		&s.OKATO, &s.OKTMO, &s.IFNSFL, &s.IFNSUL, &s.CadNum,
+		&s.PrevID, &s.NextID, &s.OperStatus, &s.StartDate, &s.EndDate)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Room - версия помещения (строка rooms)
+type Room struct {
+	RoomID     string        `json:"roomid"`
+	RoomGUID   string        `json:"roomguid"`
+	HouseGUID  string        `json:"houseguid"`
+	FlatNumber string        `json:"flatnumber,omitempty"`
+	FlatType   int           `json:"flattype"`
+	RoomNumber string        `json:"roomnumber,omitempty"`
+	RoomType   int           `json:"roomtype"`
+	PostalCode model.NullInt `json:"postalcode"`
+	CadNum     string        `json:"cadnum,omitempty"`
+	RoomCadNum string        `json:"roomcadnum,omitempty"`
+	PrevID     string        `json:"previd,omitempty"`
+	NextID     string        `json:"nextid,omitempty"`
+	OperStatus int           `json:"operstatus"`
+	StartDate  model.Date    `json:"startdate"`
+	EndDate    model.Date    `json:"enddate"`
+}
+
+const roomColumns = `roomid, roomguid, houseguid, coalesce(flatnumber, ''), flattype, coalesce(roomnumber, ''), roomtype,
+	postalcode, coalesce(cadnum, ''), coalesce(roomcadnum, ''), coalesce(previd, ''), coalesce(nextid, ''), operstatus, startdate, enddate`
+
+func scanRoom(row interface{ Scan(...interface{}) error }) (*Room, error) {
+	var r Room
+	err := row.Scan(&r.RoomID, &r.RoomGUID, &r.HouseGUID, &r.FlatNumber, &r.FlatType, &r.RoomNumber, &r.RoomType,
+		&r.PostalCode, &r.CadNum, &r.RoomCadNum, &r.PrevID, &r.NextID, &r.OperStatus, &r.StartDate, &r.EndDate)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,119 @@
+// Package source - получение выгрузок ФИАС: SOAP сервис, загрузка и локальные архивы
+package source
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultServiceURL - SOAP сервис ФИАС по умолчанию
+const DefaultServiceURL = "http://fias.nalog.ru/WebServices/Public/DownloadService.asmx"
+
+// Config - настройки обращения к сервису ФИАС
+type Config struct {
+	URL             string
+	Timeout         time.Duration
+	ConnectTimeout  time.Duration
+	DownloadTimeout time.Duration
+	Proxy           string
+	CAFile          string
+	UserAgent       string
+}
+
+// userAgentTransport - подставляет User-Agent во все запросы
+type userAgentTransport struct {
+	http.RoundTripper
+	userAgent string
+}
+
+func (t *userAgentTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.userAgent != "" && req.Header.Get("User-Agent") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+	return t.RoundTripper.RoundTrip(req)
+}
+
+// ProgressFunc - обертка reader для вывода прогресса, возвращает функцию завершения
+type ProgressFunc func(r io.Reader, size int64, logger *slog.Logger) (io.Reader, func())
+
+// Client - клиент сервиса ФИАС, один http клиент для запроса версии и загрузки архива
+type Client struct {
+	URL             string
+	HTTP            *http.Client
+	Timeout         time.Duration
+	DownloadTimeout time.Duration
+	Progress        ProgressFunc
+	Logger          *slog.Logger
+}
+
+// NewClient - клиент с настройками c
+func NewClient(c Config) (*Client, error) {
+	httpClient, err := NewHTTPClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	url := c.URL
+	if url == "" {
+		url = DefaultServiceURL
+	}
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+
+	return &Client{
+		URL:             url,
+		HTTP:            httpClient,
+		Timeout:         timeout,
+		DownloadTimeout: c.DownloadTimeout,
+		Logger:          slog.Default(),
+	}, nil
+}
+
+// NewHTTPClient - создаем http клиент с прокси, CA и таймаутами из настроек
+func NewHTTPClient(c Config) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   c.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = c.ConnectTimeout
+	transport.ResponseHeaderTimeout = c.Timeout
+
+	if c.Proxy != "" {
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("service.proxy: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("service.ca_file: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("service.ca_file: в %s нет сертификатов", c.CAFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+
+	return &http.Client{
+		Transport: &userAgentTransport{RoundTripper: transport, userAgent: c.UserAgent},
+	}, nil
+}
@@ -0,0 +1,66 @@
+package source
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	unarr "github.com/gen2brain/go-unarr"
+)
+
+// Download - Грузим файл path из ФИАС в fileName
+func (c *Client) Download(ctx context.Context, path string, fileName string) error {
+	output, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	if c.DownloadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.DownloadTimeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", path, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s вернул %s", path, response.Status)
+	}
+
+	logger := c.Logger.With("file", fileName)
+	logger.Info("Загружаем файл", "url", path, "size", response.ContentLength)
+
+	var reader io.Reader = response.Body
+	if c.Progress != nil {
+		var finish func()
+		reader, finish = c.Progress(response.Body, response.ContentLength, logger)
+		defer finish()
+	}
+
+	_, err = io.Copy(output, reader)
+	return err
+}
+
+// Extract - распаковываем архив fileName в каталог dir
+func Extract(fileName string, dir string) error {
+	a, err := unarr.NewArchive(fileName)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	_, err = a.Extract(dir)
+	return err
+}
@@ -0,0 +1,74 @@
+package source
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDownload(t *testing.T) {
+	archive := strings.Repeat("Rar!", 1024)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/fias_xml.rar" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+		io.WriteString(w, archive)
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name string
+		path string
+		err  string
+	}{
+		{"архив", "/fias_xml.rar", ""},
+		{"нет файла", "/missing.rar", "404"},
+		{"локальный файл", "file:///etc/hostname", "unsupported protocol scheme"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := testClient(t, srv.URL)
+			var progress int64
+			c.Progress = func(r io.Reader, size int64, logger *slog.Logger) (io.Reader, func()) {
+				return r, func() { progress = size }
+			}
+
+			fileName := filepath.Join(t.TempDir(), "fias.rar")
+			url := tt.path
+			if strings.HasPrefix(url, "/") {
+				url = srv.URL + url
+			}
+			err := c.Download(context.Background(), url, fileName)
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ошибка %v, ожидается %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			data, err := os.ReadFile(fileName)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != archive {
+				t.Errorf("загружено %d байт, ожидается %d", len(data), len(archive))
+			}
+			if progress != int64(len(archive)) {
+				t.Errorf("прогресс: размер %d, ожидается %d", progress, len(archive))
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package source
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	unarr "github.com/gen2brain/go-unarr"
+)
+
+// fileDatePattern - дата выгрузки в имени файла ФИАС (AS_HOUSE_20190101_...)
+var fileDatePattern = regexp.MustCompile("^AS_[A-Z_]+_([0-9]{8})_")
+
+// Local - уже загруженный архив или распакованный каталог ФИАС
+type Local struct {
+	Path  string
+	IsDir bool
+	// Date - дата выгрузки ГГГГММДД из имен файлов или дата изменения; это не VersionId
+	// сервиса, его находит Client.VersionByDate
+	Date int
+}
+
+// OpenLocal - открываем локальный источник: путь к архиву, каталогу или file:// URL
+func OpenLocal(source string) (*Local, error) {
+	path := source
+	if strings.HasPrefix(source, "file://") {
+		u, err := url.Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("разбор %s: %w", source, err)
+		}
+		path = u.Path
+		if len(path) > 2 && path[0] == '/' && path[2] == ':' {
+			// file:///C:/fias/fias_xml.rar
+			path = path[1:]
+		}
+	}
+
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	src := &Local{Path: path, IsDir: fi.IsDir()}
+
+	var names []string
+	if src.IsDir {
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+	} else {
+		names, err = archiveEntries(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	src.Date = DateFromNames(names)
+	if src.Date == 0 {
+		src.Date, _ = strconv.Atoi(fi.ModTime().Format("20060102"))
+		slog.Warn("Дата выгрузки не найдена в именах файлов, берем дату изменения", "source", path, "date", src.Date)
+	}
+
+	return src, nil
+}
+
+// archiveEntries - имена файлов в архиве
+func archiveEntries(path string) ([]string, error) {
+	a, err := unarr.NewArchive(path)
+	if err != nil {
+		return nil, err
+	}
+	defer a.Close()
+
+	var names []string
+	for {
+		err := a.Entry()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, a.Name())
+	}
+
+	return names, nil
+}
+
+// DateFromNames - дата выгрузки (ГГГГММДД) по самой свежей дате в именах файлов
+func DateFromNames(names []string) int {
+	date := 0
+	for _, name := range names {
+		m := fileDatePattern.FindStringSubmatch(strings.ToUpper(name[strings.LastIndexAny(name, `/\`)+1:]))
+		if m == nil {
+			continue
+		}
+		if v, _ := strconv.Atoi(m[1]); v > date {
+			date = v
+		}
+	}
+
+	return date
+}
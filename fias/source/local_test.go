@@ -0,0 +1,45 @@
+package source
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDateFromNames(t *testing.T) {
+	tests := []struct {
+		names []string
+		date  int
+	}{
+		{[]string{"AS_ADDROBJ_20190507_a.XML", "fias/AS_HOUSE_20190514_b.XML", "AS_ROOM.XSD"}, 20190514},
+		{[]string{`C:\fias\as_stead_20181231_c.xml`}, 20181231},
+		{[]string{"readme.txt", "AS_FLATTYPE_2_250_08_04_01_01.xsd"}, 0},
+		{nil, 0},
+	}
+	for _, tt := range tests {
+		if got := DateFromNames(tt.names); got != tt.date {
+			t.Errorf("DateFromNames(%v) = %d, ожидается %d", tt.names, got, tt.date)
+		}
+	}
+}
+
+func TestOpenLocal(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "AS_HOUSE_20190514_b.XML"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{dir, "file://" + filepath.ToSlash(dir)} {
+		src, err := OpenLocal(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if src.Path != dir || !src.IsDir || src.Date != 20190514 {
+			t.Errorf("OpenLocal(%q) = %+v", source, src)
+		}
+	}
+
+	if _, err := OpenLocal(filepath.Join(dir, "missing.rar")); err == nil {
+		t.Error("нет ошибки для отсутствующего файла")
+	}
+}
@@ -0,0 +1,141 @@
+package source
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// MyRespEnvelope - запрос новых файлов
+type MyRespEnvelope struct {
+	XMLName xml.Name
+	Body    Body
+}
+
+// Body - тело ответа запроса новых файлов
+type Body struct {
+	XMLName     xml.Name
+	GetResponse GetLastDownloadFileInfoResponse `xml:"GetLastDownloadFileInfoResponse"`
+}
+
+// GetLastDownloadFileInfoResponse - структрура запроса новых файлов
+type GetLastDownloadFileInfoResponse struct {
+	XMLName                       xml.Name                      `xml:"GetLastDownloadFileInfoResponse"`
+	GetLastDownloadFileInfoResult GetLastDownloadFileInfoResult `xml:"GetLastDownloadFileInfoResult"`
+}
+
+// GetLastDownloadFileInfoResult - структрура ответа на запрос новых файлов
+type GetLastDownloadFileInfoResult struct {
+	XMLName            xml.Name `xml:"GetLastDownloadFileInfoResult"`
+	VersionId          int      `xml:"VersionId"`
+	TextVersion        string   `xml:"TextVersion"`
+	FiasCompleteDbfUrl string   `xml:"FiasCompleteDbfUrl"`
+	FiasCompleteXmlUrl string   `xml:"FiasCompleteXmlUrl"`
+	FiasDeltaDbfUrl    string   `xml:"FiasDeltaDbfUrl"`
+	FiasDeltaXmlUrl    string   `xml:"FiasDeltaXmlUrl"`
+	Kladr4ArjUrl       string   `xml:"Kladr4ArjUrl"`
+	Kladr47ZUrl        string   `xml:"Kladr47ZUrl"`
+}
+
+var lastDownloadFileInfoRequest = []byte(`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:dow="http://fias.nalog.ru/WebServices/Public/DownloadService.asmx">
+   <soap:Header/>
+   <soap:Body>
+      <dow:GetLastDownloadFileInfo/>
+   </soap:Body>
+</soap:Envelope>`)
+
+// LastVersion - сведения о последней версии ФИАС (GetLastDownloadFileInfo)
+func (c *Client) LastVersion(ctx context.Context) (*GetLastDownloadFileInfoResult, error) {
+	ver := &MyRespEnvelope{}
+	if err := c.call(ctx, lastDownloadFileInfoRequest, ver); err != nil {
+		return nil, err
+	}
+
+	return &ver.Body.GetResponse.GetLastDownloadFileInfoResult, nil
+}
+
+// DownloadFileInfo - сведения о версии из ответа на запрос всех версий
+type DownloadFileInfo struct {
+	VersionId          int    `xml:"VersionId"`
+	TextVersion        string `xml:"TextVersion"`
+	FiasCompleteXmlUrl string `xml:"FiasCompleteXmlUrl"`
+	FiasDeltaXmlUrl    string `xml:"FiasDeltaXmlUrl"`
+}
+
+// allDownloadFileInfoEnvelope - ответ на запрос всех версий
+type allDownloadFileInfoEnvelope struct {
+	Body struct {
+		Versions []DownloadFileInfo `xml:"GetAllDownloadFileInfoResponse>GetAllDownloadFileInfoResult>DownloadFileInfo"`
+	}
+}
+
+var allDownloadFileInfoRequest = []byte(`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:dow="http://fias.nalog.ru/WebServices/Public/DownloadService.asmx">
+   <soap:Header/>
+   <soap:Body>
+      <dow:GetAllDownloadFileInfo/>
+   </soap:Body>
+</soap:Envelope>`)
+
+// AllVersions - сведения обо всех опубликованных версиях ФИАС (GetAllDownloadFileInfo)
+func (c *Client) AllVersions(ctx context.Context) ([]DownloadFileInfo, error) {
+	all := &allDownloadFileInfoEnvelope{}
+	if err := c.call(ctx, allDownloadFileInfoRequest, all); err != nil {
+		return nil, err
+	}
+
+	return all.Body.Versions, nil
+}
+
+// VersionByDate - VersionId выгрузки от даты date (ГГГГММДД): в TextVersion сервис пишет
+// дату выгрузки вида "БД ФИАС от 14.05.2019"
+func (c *Client) VersionByDate(ctx context.Context, date int) (int, error) {
+	versions, err := c.AllVersions(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	text := fmt.Sprintf("%02d.%02d.%04d", date%100, date/100%100, date/10000)
+	for _, v := range versions {
+		if strings.Contains(v.TextVersion, text) {
+			return v.VersionId, nil
+		}
+	}
+
+	return 0, fmt.Errorf("сервис %s не знает выгрузку от %s", c.URL, text)
+}
+
+// call - POST запроса request к сервису и разбор ответа в v
+func (c *Client) call(ctx context.Context, request []byte, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.URL, bytes.NewBuffer(request))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("сервис %s вернул %s", c.URL, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if err := xml.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("разбор ответа сервиса %s: %w", c.URL, err)
+	}
+
+	return nil
+}
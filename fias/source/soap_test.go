@@ -0,0 +1,146 @@
+package source
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const lastVersionResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>
+    <GetLastDownloadFileInfoResponse xmlns="http://fias.nalog.ru/WebServices/Public/DownloadService.asmx/">
+      <GetLastDownloadFileInfoResult>
+        <VersionId>634</VersionId>
+        <TextVersion>БД ФИАС от 14.05.2019</TextVersion>
+        <FiasCompleteXmlUrl>https://fias-file.nalog.ru/fias_xml.rar</FiasCompleteXmlUrl>
+        <FiasDeltaXmlUrl>https://fias-file.nalog.ru/fias_delta_xml.rar</FiasDeltaXmlUrl>
+      </GetLastDownloadFileInfoResult>
+    </GetLastDownloadFileInfoResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+const allVersionsResponse = `<?xml version="1.0" encoding="utf-8"?>
+<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope">
+  <soap:Body>
+    <GetAllDownloadFileInfoResponse xmlns="http://fias.nalog.ru/WebServices/Public/DownloadService.asmx/">
+      <GetAllDownloadFileInfoResult>
+        <DownloadFileInfo><VersionId>633</VersionId><TextVersion>БД ФИАС от 07.05.2019</TextVersion></DownloadFileInfo>
+        <DownloadFileInfo><VersionId>634</VersionId><TextVersion>БД ФИАС от 14.05.2019</TextVersion></DownloadFileInfo>
+      </GetAllDownloadFileInfoResult>
+    </GetAllDownloadFileInfoResponse>
+  </soap:Body>
+</soap:Envelope>`
+
+// testClient - клиент сервиса по адресу url
+func testClient(t *testing.T, url string) *Client {
+	t.Helper()
+	c, err := NewClient(Config{URL: url, Timeout: 5 * time.Second, ConnectTimeout: time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	return c
+}
+
+// soapServer - сервис, отвечающий body со статусом status на запросы с action в теле
+func soapServer(t *testing.T, status int, responses map[string]string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || !strings.HasPrefix(r.Header.Get("Content-Type"), "application/soap+xml") {
+			t.Errorf("запрос %s %s", r.Method, r.Header.Get("Content-Type"))
+		}
+		if r.Header.Get("User-Agent") == "" {
+			t.Error("нет User-Agent")
+		}
+		for action, body := range responses {
+			if strings.Contains(string(request), action) {
+				w.WriteHeader(status)
+				io.WriteString(w, body)
+				return
+			}
+		}
+		http.Error(w, "unknown action", http.StatusBadRequest)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLastVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		version int
+		err     string
+	}{
+		{"ответ", http.StatusOK, lastVersionResponse, 634, ""},
+		{"ошибка сервиса", http.StatusInternalServerError, "", 0, "500"},
+		{"не XML", http.StatusOK, "<html>", 0, "разбор ответа"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := soapServer(t, tt.status, map[string]string{"GetLastDownloadFileInfo": tt.body})
+			info, err := testClient(t, srv.URL).LastVersion(context.Background())
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ошибка %v, ожидается %q", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.VersionId != tt.version || info.FiasCompleteXmlUrl != "https://fias-file.nalog.ru/fias_xml.rar" {
+				t.Errorf("ответ %+v", info)
+			}
+		})
+	}
+}
+
+func TestLastVersionTimeout(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	c := testClient(t, srv.URL)
+	c.Timeout = 100 * time.Millisecond
+	if _, err := c.LastVersion(context.Background()); err == nil {
+		t.Fatal("нет ошибки таймаута")
+	}
+}
+
+func TestVersionByDate(t *testing.T) {
+	srv := soapServer(t, http.StatusOK, map[string]string{"GetAllDownloadFileInfo": allVersionsResponse})
+	c := testClient(t, srv.URL)
+
+	tests := []struct {
+		date    int
+		version int
+	}{
+		{20190514, 634},
+		{20190507, 633},
+		{20190101, 0},
+	}
+	for _, tt := range tests {
+		version, err := c.VersionByDate(context.Background(), tt.date)
+		if tt.version == 0 {
+			if err == nil {
+				t.Errorf("VersionByDate(%d) = %d, ожидается ошибка", tt.date, version)
+			}
+			continue
+		}
+		if err != nil || version != tt.version {
+			t.Errorf("VersionByDate(%d) = %d, %v; ожидается %d", tt.date, version, err, tt.version)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	pb "gopkg.in/cheggaaa/pb.v1"
+)
+
+// interactive - выводить ли прогресс-бары pb (false - периодические строки в лог)
+var interactive = true
+
+// progressInterval - период вывода прогресса в неинтерактивном режиме
+var progressInterval = 30 * time.Second
+
+var dsnPasswordPattern = regexp.MustCompile(`(password=)('(\\'|[^'])*'|\S+)`)
+var urlPasswordPattern = regexp.MustCompile(`(://[^:/@\s]+:)[^@\s]+@`)
+
+// PassThru - структура для вывода кол-ва считанных байт
+type PassThru struct {
+	io.Reader
+	total  int64 // Total # of bytes transferred
+	size   int64
+	last   time.Time
+	logger *slog.Logger
+}
+
+// setupLogger - настраиваем уровень и формат (text - logfmt, json) логгера
+func setupLogger(level string, format string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	}
+	slog.SetDefault(slog.New(handler))
+
+	return nil
+}
+
+// redactDSN - скрываем пароль в строке подключения
+func redactDSN(dsn string) string {
+	dsn = dsnPasswordPattern.ReplaceAllString(dsn, "${1}***")
+	return urlPasswordPattern.ReplaceAllString(dsn, "${1}***@")
+}
+
+// newProgressReader - оборачиваем reader для вывода прогресса, возвращаем функцию завершения
+func newProgressReader(r io.Reader, size int64, logger *slog.Logger) (io.Reader, func()) {
+	if interactive {
+		bar := pb.New64(size).SetUnits(pb.U_BYTES)
+		bar.Start()
+		return bar.NewProxyReader(r), bar.Finish
+	}
+
+	pt := &PassThru{Reader: r, size: size, last: time.Now(), logger: logger}
+	return pt, func() {
+		pt.logger.Info("Обработано", "bytes", pt.total, "size", pt.size)
+	}
+}
+
+func (pt *PassThru) Read(p []byte) (int, error) {
+	n, err := pt.Reader.Read(p)
+	pt.total += int64(n)
+
+	if time.Since(pt.last) >= progressInterval {
+		pt.last = time.Now()
+		args := []any{"bytes", pt.total, "size", pt.size}
+		if pt.size > 0 {
+			args = append(args, "percent", pt.total*100/pt.size)
+		}
+		pt.logger.Info("Прогресс", args...)
+	}
+
+	return n, err
+}
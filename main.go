@@ -1,750 +1,543 @@
 package main
 
 import (
-	"bytes"
+	"context"
 	"database/sql"
-	"encoding/xml"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
-	"reflect"
-	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
-	unarr "github.com/gen2brain/go-unarr"
+	"github.com/SliderVM/FIASParse/fias/api"
+	"github.com/SliderVM/FIASParse/fias/events"
+	"github.com/SliderVM/FIASParse/fias/loader"
+	"github.com/SliderVM/FIASParse/fias/query"
+	"github.com/SliderVM/FIASParse/fias/source"
 	_ "github.com/lib/pq"
 	// "github.com/mholt/archiver"
-
-	"github.com/spf13/viper"
-	pb "gopkg.in/cheggaaa/pb.v1"
-	"gopkg.in/doug-martin/goqu.v3"
-	_ "gopkg.in/doug-martin/goqu.v3/adapters/postgres"
 )
 
 var dirName string
-var fileName string 
-
-var ACTSTAT_PATTERN = regexp.MustCompile("^(AS_ACTSTAT_)[0-9]{8}_.+")
-var ADDROBJ_PATTERN = regexp.MustCompile("^(AS_ADDROBJ_)[0-9]{8}_.+")
-var CENTERST_PATTERN = regexp.MustCompile("^(AS_CENTERST_)[0-9]{8}_.+")
-var CURENTST_PATTERN = regexp.MustCompile("^(AS_CURENTST_)[0-9]{8}_.+")
-var DEL_ADDROBJ_PATTERN = regexp.MustCompile("^(AS_DEL_ADDROBJ_)[0-9]{8}_.+")
-var DEL_HOUSE_PATTERN = regexp.MustCompile("^(AS_DEL_HOUSE_)[0-9]{8}_.+")
-var DEL_HOUSEINT_PATTERN = regexp.MustCompile("^(AS_DEL_HOUSEINT_)[0-9]{8}_.+")
-var DEL_NORMDOC_PATTERN = regexp.MustCompile("^(AS_DEL_NORMDOC_)[0-9]{8}_.+")
-var ESTSTAT_PATTERN = regexp.MustCompile("^(AS_ESTSTAT_)[0-9]{8}_.+")
-var HOUSE_PATTERN = regexp.MustCompile("^(AS_HOUSE_)[0-9]{8}_.+")
-var HOUSEINT_PATTERN = regexp.MustCompile("^(AS_HOUSEINT_)[0-9]{8}_.+")
-var HSTSTAT_PATTERN = regexp.MustCompile("^(AS_HSTSTAT_)[0-9]{8}_.+")
-var INTVSTAT_PATTERN = regexp.MustCompile("^(AS_INTVSTAT_)[0-9]{8}_.+")
-var LANDMARK_PATTERN = regexp.MustCompile("^(AS_LANDMARK_)[0-9]{8}_.+")
-var NDOCTYPE_PATTERN = regexp.MustCompile("^(AS_NDOCTYPE_)[0-9]{8}_.+")
-var NORMDOC_PATTERN = regexp.MustCompile("^(AS_NORMDOC_)[0-9]{8}_.+")
-var OPERSTAT_PATTERN = regexp.MustCompile("^(AS_OPERSTAT_)[0-9]{8}_.+")
-var SOCRBASE_PATTERN = regexp.MustCompile("^(AS_SOCRBASE_)[0-9]{8}_.+")
-var STRSTAT_PATTERN = regexp.MustCompile("^(AS_STRSTAT_)[0-9]{8}_.+")
-var STEAD_PATTERN = regexp.MustCompile("^(AS_STEAD_)[0-9]{8}_.+")
-var ROOM_PATTERN = regexp.MustCompile("^(AS_ROOM_)[0-9]{8}_.+")
-
-// PassThru - структура для вывода кол-ва считанных байт
-type PassThru struct {
-	io.Reader
-	total int64 // Total # of bytes transferred
-}
-
-// MyRespEnvelope - запрос новых файлов
-type MyRespEnvelope struct {
-	XMLName xml.Name
-	Body    Body
-}
+var fileName string
 
-// Body - тело ответа запроса новых файлов
-type Body struct {
-	XMLName     xml.Name
-	GetResponse GetLastDownloadFileInfoResponse `xml:"GetLastDownloadFileInfoResponse"`
-}
+// publisher - отправка событий по журналу изменений, nil если события выключены
+var publisher events.Publisher
 
-// GetLastDownloadFileInfoResponse - структрура запроса новых файлов
-type GetLastDownloadFileInfoResponse struct {
-	XMLName                       xml.Name                      `xml:"GetLastDownloadFileInfoResponse"`
-	GetLastDownloadFileInfoResult GetLastDownloadFileInfoResult `xml:"GetLastDownloadFileInfoResult"`
-}
+// checkNewFile - ссылка на архив новой версии или пустая строка, если версия уже загружена
+func checkNewFile(client *source.Client, db *sql.DB) (path string, versionID int, err error) {
+	info, err := client.LastVersion(context.Background())
+	if err != nil {
+		slog.Error("Ошибка запроса новых файлов", "url", client.URL, "err", err)
+		return "", 0, err
+	}
+	versionID = info.VersionId
 
-// GetLastDownloadFileInfoResult - структрура ответа на запрос новых файлов
-type GetLastDownloadFileInfoResult struct {
-	XMLName            xml.Name `xml:"GetLastDownloadFileInfoResult"`
-	VersionId          int      `xml:"VersionId"`
-	TextVersion        string   `xml:"TextVersion"`
-	FiasCompleteDbfUrl string   `xml:"FiasCompleteDbfUrl"`
-	FiasCompleteXmlUrl string   `xml:"FiasCompleteXmlUrl"`
-	FiasDeltaDbfUrl    string   `xml:"FiasDeltaDbfUrl"`
-	FiasDeltaXmlUrl    string   `xml:"FiasDeltaXmlUrl"`
-	Kladr4ArjUrl       string   `xml:"Kladr4ArjUrl"`
-	Kladr47ZUrl        string   `xml:"Kladr47ZUrl"`
-}
+	fileVersion, err := loader.CurrentVersion(db)
+	if err != nil {
+		slog.Error("Ошибка чтения настройки TextVersion", "err", err)
+		return "", versionID, err
+	}
 
-// ActualStatus - Статус актуальности ФИАС
-type ActualStatus struct {
-	ACTSTATID int    `xml:"ACTSTATID,attr"`
-	NAME      string `xml:"NAME,attr"`
-}
+	if fileVersion != strconv.Itoa(versionID) {
+		path = info.FiasCompleteXmlUrl
+		if err := loader.RecordLoad(db, versionID, redactDSN(path)); err != nil {
+			slog.Error("Ошибка при записи версии файла", "version", versionID, "err", err)
+			return "", versionID, err
+		}
 
-// Object - Классификатор адресообразующих элементов
-type Object struct {
-	AOGUID     string `xml:"AOGUID,attr"`
-	FORMALNAME string `xml:"FORMALNAME,attr"`
-	REGIONCODE string `xml:"REGIONCODE,attr"`
-	AUTOCODE   string `xml:"AUTOCODE,attr"`
-	AREACODE   string `xml:"AREACODE,attr"`
-	CITYCODE   string `xml:"CITYCODE,attr"`
-	CTARCODE   string `xml:"CTARCODE,attr"`
-	PLACECODE  string `xml:"PLACECODE,attr"`
-	STREETCODE string `xml:"STREETCODE,attr"`
-	EXTRCODE   string `xml:"EXTRCODE,attr"`
-	SEXTCODE   string `xml:"SEXTCODE,attr"`
-	OFFNAME    string `xml:"OFFNAME,attr"`
-	POSTALCODE string `xml:"POSTALCODE,attr"`
-	IFNSFL     string `xml:"IFNSFL,attr"`
-	TERRIFNSFL string `xml:"TERRIFNSFL,attr"`
-	IFNSUL     string `xml:"IFNSUL,attr"`
-	TERRIFNSUL string `xml:"TERRIFNSUL,attr"`
-	OKATO      string `xml:"OKATO,attr"`
-	OKTMO      string `xml:"OKTMO,attr"`
-	UPDATEDATE string `xml:"UPDATEDATE,attr"`
-	SHORTNAME  string `xml:"SHORTNAME,attr"`
-	AOLEVEL    int    `xml:"AOLEVEL,attr"`
-	PARENTGUID string `xml:"PARENTGUID,attr"`
-	AOID       string `xml:"AOID,attr"`
-	PREVID     string `xml:"PREVID,attr"`
-	NEXTID     string `xml:"NEXTID,attr"`
-	CODE       string `xml:"CODE,attr"`
-	PLAINCODE  string `xml:"PLAINCODE,attr"`
-	ACTSTATUS  int    `xml:"ACTSTATUS,attr"`
-	CENTSTATUS int    `xml:"CENTSTATUS,attr"`
-	OPERSTATUS int    `xml:"OPERSTATUS,attr"`
-	CURRSTATUS int    `xml:"CURRSTATUS,attr"`
-	STARTDATE  string `xml:"STARTDATE,attr"`
-	ENDDATE    string `xml:"ENDDATE,attr"`
-	NORMDOC    string `xml:"NORMDOC,attr"`
-	LIVESTATUS byte   `xml:"LIVESTATUS,attr"`
-	CADNUM     string `xml:"CADNUM,attr"`
-	DIVTYPE    int    `xml:"DIVTYPE,attr"`
-}
+		slog.Info("Найдена новая версия", "version", versionID, "previous", fileVersion)
+		return path, versionID, err
+	}
 
-// CenterStatus - Статус центра
-type CenterStatus struct {
-	CENTERSTID int    `xml:"CENTERSTID,attr"`
-	NAME       string `xml:"NAME,attr"`
+	return "", versionID, err
 }
 
-// CurrentStatus - Статус актуальности КЛАДР 4.0
-type CurrentStatus struct {
-	CURENTSTID int    `xml:"CURENTSTID,attr"`
-	NAME       string `xml:"NAME,attr"`
+// loadedVersion - VersionId из TextVersion для режимов без проверки новой версии:
+// архив скачан при последней проверке, его версия уже записана
+func loadedVersion(db *sql.DB) (int, error) {
+	value, err := loader.CurrentVersion(db)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("TextVersion %q не VersionId сервиса: %w", value, err)
+	}
+	return version, nil
 }
 
-// EstateStatus - Признак владения
-type EstateStatus struct {
-	ESTSTATID int    `xml:"ESTSTATID,attr"`
-	NAME      string `xml:"NAME,attr"`
-	SHORTNAME string `xml:"SHORTNAME,attr"`
-}
+// DownLoadFile - Грузим файл из ФИАС
+func DownLoadFile(client *source.Client, path string) error {
+	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
+		os.RemoveAll(dirName)
+		os.Remove(fileName)
+	}
 
-// House - Сведения по номерам домов улиц городов и населенных пунктов
-type House struct {
-	PostalCode string `xml:"POSTALCODE,attr"`
-	RegionCode string `xml:"REGIONCODE,attr"`
-	IFNSFL     string `xml:"IFNSFL,attr"`
-	TerrIFNSFL string `xml:"TERRIFNSFL,attr"`
-	IFNSUL     string `xml:"IFNSUL,attr"`
-	TerrIFNSUL string `xml:"TERRIFNSUL,attr"`
-	OKATO      string `xml:"OKATO,attr"`
-	OKTMO      string `xml:"OKTMO,attr"`
-	UPDATEDATE string `xml:"UPDATEDATE,attr"`
-	HouseNum   string `xml:"HOUSENUM,attr"`
-	ESTStatus  int    `xml:"ESTSTATUS,attr"`
-	//ESTStatus string `xml:"ESTSTATUS,attr"`
-	BuildNum  string `xml:"BUILDNUM,attr"`
-	StrucNum  string `xml:"STRUCNUM,attr"`
-	STRStatus int    `xml:"STRSTATUS,attr"`
-	//STRStatus string `xml:"STRSTATUS,attr"`
-	HouseID    string `xml:"HOUSEID,attr"`
-	HouseGUID  string `xml:"HOUSEGUID,attr"`
-	AOGUID     string `xml:"AOGUID,attr"`
-	STARTDATE  string `xml:"STARTDATE,attr"`
-	ENDDATE    string `xml:"ENDDATE,attr"`
-	StatStatus int    `xml:"STATSTATUS,attr"`
-	//StatStatus string `xml:"STATSTATUS,attr"`
-	NormDoc string `xml:"NORMDOC,attr"`
-	Counter int    `xml:"COUNTER,attr"`
-	//Counter string `xml:"COUNTER,attr"`
-	CadNum  string `xml:"CADNUM,attr"`
-	DviType int    `xml:"DVITYPE,attr"`
-	//DviType string `xml:"DVITYPE,attr"`
+	err := client.Download(context.Background(), path, fileName)
+	if err != nil {
+		slog.Error("Ошибка при загрузке файла", "url", path, "file", fileName, "err", err)
+	}
+	return err
 }
 
-// HouseInterval - Интервалы домов
-type HouseInterval struct {
-	POSTALCODE string `xml:"POSTALCODE,attr"`
-	IFNSFL     string `xml:"IFNSFL,attr"`
-	TERRIFNSFL string `xml:"TERRIFNSFL,attr"`
-	IFNSUL     string `xml:"IFNSUL,attr"`
-	TERRIFNSUL string `xml:"TERRIFNSUL,attr"`
-	OKATO      string `xml:"OKATO,attr"`
-	OKTMO      string `xml:"OKTMO,attr"`
-	UPDATEDATE string `xml:"UPDATEDATE,attr"`
-	INTSTART   int    `xml:"INTSTART,attr"`
-	INTEND     int    `xml:"INTEND,attr"`
-	HOUSEINTID string `xml:"HOUSEINTID,attr"`
-	INTGUID    string `xml:"INTGUID,attr"`
-	AOGUID     string `xml:"AOGUID,attr"`
-	STARTDATE  string `xml:"STARTDATE,attr"`
-	ENDDATE    string `xml:"ENDDATE,attr"`
-	INTSTATUS  int    `xml:"INTSTATUS,attr"`
-	NORMDOC    string `xml:"NORMDOC,attr"`
-	COUNTER    int    `xml:"COUNTER,attr"`
+// UnRar - распаковываем архив
+func UnRar(fileName string) error {
+	err := source.Extract(fileName, "FIAS")
+	if err != nil {
+		slog.Error("Ошибка распаковки архива", "file", fileName, "err", err)
+	}
+	return err
 }
 
-// HouseStateStatus - Статус состояния домов
-type HouseStateStatus struct {
-	HOUSESTID int    `xml:"HOUSESTID,attr"`
-	NAME      string `xml:"NAME,attr"`
+// newPublisher - отправка событий из секции [events]
+func newPublisher(cfg EventsConfig) (events.Publisher, error) {
+	switch cfg.Broker {
+	case "kafka":
+		return events.NewKafkaPublisher(strings.Split(cfg.URL, ","), cfg.Topic), nil
+	case "file":
+		if cfg.URL == "-" {
+			return events.NewWriterPublisher(struct{ io.Writer }{os.Stdout}), nil
+		}
+		file, err := os.OpenFile(cfg.URL, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, err
+		}
+		return events.NewWriterPublisher(file), nil
+	}
+	return events.NewNATSPublisher(cfg.URL, cfg.Topic)
 }
 
-// IntervalStatus - Статус интервала домов
-type IntervalStatus struct {
-	INTVSTATID int    `xml:"INTVSTATID,attr"`
-	NAME       string `xml:"NAME,attr"`
+// newLoader - загрузчик с настройками из конфига
+func newLoader(cfg *Config, db *sql.DB, version int) *loader.Loader {
+	l := loader.New(db)
+	l.OnError = cfg.OnDecodeError
+	l.QuarantineStorage = cfg.QuarantineStorage
+	l.QuarantineDir = cfg.QuarantineDir
+	l.Schemas = cfg.Schemas
+	l.FailOnUnknown = cfg.FailOnUnknown
+	l.Version = version
+	l.Sink = newSink(cfg, db)
+	l.Progress = newProgressReader
+	l.Logger = slog.Default()
+	return l
 }
 
-// Landmark - Описание мест расположения  имущественных объектов
-type Landmark struct {
-	LOCATION   string `xml:"LOCATION,attr"`
-	REGIONCODE string `xml:"REGIONCODE,attr"`
-	POSTALCODE string `xml:"POSTALCODE,attr"`
-	IFNSFL     string `xml:"IFNSFL,attr"`
-	TERRIFNSFL string `xml:"TERRIFNSFL,attr"`
-	IFNSUL     string `xml:"IFNSUL,attr"`
-	TERRIFNSUL string `xml:"TERRIFNSUL,attr"`
-	OKATO      string `xml:"OKATO,attr"`
-	OKTMO      string `xml:"OKTMO,attr"`
-	UPDATEDATE string `xml:"UPDATEDATE,attr"`
-	LANDID     string `xml:"LANDID,attr"`
-	LANDGUID   string `xml:"LANDGUID,attr"`
-	AOGUID     string `xml:"AOGUID,attr"`
-	STARTDATE  string `xml:"STARTDATE,attr"`
-	ENDDATE    string `xml:"ENDDATE,attr"`
-	NORMDOC    string `xml:"NORMDOC,attr"`
-	CADNUM     string `xml:"CADNUM,attr"`
-}
+// newSink - приемники записей из sink.types, несколько - через MultiSink
+func newSink(cfg *Config, db *sql.DB) loader.Sink {
+	var sinks loader.MultiSink
+	for _, t := range cfg.Sink.Types {
+		switch t {
+		case "postgres":
+			pg := loader.NewPostgresSink(db)
+			pg.MaxDropPercent = cfg.Sanity.MaxDropPercent
+			pg.TableMaxDropPercent = cfg.Sanity.Tables
+			pg.Diff = cfg.Diff
+			pg.KeepVersions = cfg.KeepVersions
+			sinks = append(sinks, pg)
+		case "file":
+			sinks = append(sinks, &loader.FileSink{Dir: cfg.Sink.ExportDir})
+		case "nop":
+			sinks = append(sinks, loader.NopSink{})
+		}
+	}
 
-// NormativeDocumentType - Тип нормативного документа
-type NormativeDocumentType struct {
-	NDTYPEID int    `xml:"NDTYPEID,attr"`
-	NAME     string `xml:"NAME,attr"`
+	if len(sinks) == 1 {
+		return sinks[0]
+	}
+	return sinks
 }
 
-// NormativeDocument - Сведения по нормативному документу, являющемуся основанием присвоения адресному элементу наименования
-type NormativeDocument struct {
-	NORMDOCID string `xml:"NORMDOCID,attr"`
-	DOCNAME   string `xml:"DOCNAME,attr"`
-	DOCDATE   string `xml:"DOCDATE,attr"`
-	DOCNUM    string `xml:"DOCNUM,attr"`
-	DOCTYPE   int    `xml:"DOCTYPE,attr"`
-	DOCIMGID  string `xml:"DOCIMGID,attr"`
-}
+// runValidation - проверка после загрузки версии version; если включен validate.fail и есть
+// нарушения или проверки не выполнились, загрузка отменяется по снимкам и возвращается ошибка
+func runValidation(cfg *Config, db *sql.DB, version int) error {
+	if !cfg.Validation.Enabled || !slices.Contains(cfg.Sink.Types, "postgres") {
+		return nil
+	}
 
-// OperationStatus - Статус действия
-type OperationStatus struct {
-	OPERSTATID int    `xml:"OPERSTATID,attr"`
-	NAME       string `xml:"NAME,attr"`
-}
+	results := loader.ValidateIntegrity(db, cfg.Validation.Sample)
+	total, failed := loader.ReportValidation(slog.Default(), results)
+	if !cfg.Validation.Fail || (total == 0 && failed == 0) {
+		return nil
+	}
 
-// Room - Классификатор помещениях
-type Room struct {
-	RoomGuid   string `xml:"ROOMGUID,attr"`
-	FlatNumber string `xml:"FLATNUMBER,attr"`
-	FlatType   int    `xml:"FLATTYPE,attr"`
-	RoomNumber string `xml:"ROOMNUMBER,attr"`
-	RoomType   int    `xml:"ROOMTYPE,attr"`
-	RegionCode string `xml:"REGIONCODE,attr"`
-	PostalCode string `xml:"POSTALCODE,attr"`
-	UpdateDate string `xml:"UPDATEDATE,attr"`
-	HouseGuid  string `xml:"HOUSEGUID,attr"`
-	RoomId     string `xml:"ROOMID,attr"`
-	PrevId     string `xml:"PREVID,attr"`
-	NextId     string `xml:"NEXTID,attr"`
-	StartDate  string `xml:"STARTDATE,attr"`
-	EndDate    string `xml:"ENDDATE,attr"`
-	LiveStatus string `xml:"LIVESTATUS,attr"`
-	NormDoc    string `xml:"NORMDOC,attr"`
-	OperStatus int    `xml:"OPERSTATUS,attr"`
-	CadNum     string `xml:"CADNUM,attr"`
-	RoomCadNum string `xml:"ROOMCADNUM,attr"`
-}
+	err := fmt.Errorf("найдено %d нарушений целостности, не выполнено проверок: %d", total, failed)
+	tables, skipped, undoErr := loader.Undo(db, version)
+	if undoErr != nil {
+		return errors.Join(err, fmt.Errorf("отмена загрузки: %w", undoErr))
+	}
+	slog.Warn("Загрузка отменена, таблицы возвращены к предыдущей версии", "tables", tables, "without_snapshot", skipped)
+	// TextVersion не меняем, чтобы отмененная выгрузка не скачивалась повторно
+	if err := loader.RecordHistory(db, version, "validation failed"); err != nil {
+		slog.Error("Ошибка при записи истории загрузок", "err", err)
+	}
 
-// AddressObjectType - Тип адресного объекта
-type AddressObjectType struct {
-	LEVEL    int    `xml:"LEVEL,attr"`
-	SCNAME   string `xml:"SCNAME,attr"`
-	SOCRNAME string `xml:"SOCRNAME,attr"`
-	KODTST   string `xml:"KOD_T_ST,attr"`
+	return err
 }
 
-// Stead - Классификатор земельных участков
-type Stead struct {
-	STEADGUID  string `xml:"STEADGUID,attr"`
-	NUMBER     string `xml:"NUMBER,attr"`
-	REGIONCODE string `xml:"REGIONCODE,attr"`
-	POSTALCODE string `xml:"POSTALCODE,attr"`
-	IFNSFL     string `xml:"IFNSFL,attr"`
-	TERRIFNSFL string `xml:"TERRIFNSFL,attr"`
-	IFNSUL     string `xml:"IFNSUL,attr"`
-	TERRIFNSUL string `xml:"TERRIFNSUL,attr"`
-	OKATO      string `xml:"OKATO,attr"`
-	OKTMO      string `xml:"OKTMO,attr"`
-	UPDATEDATE string `xml:"UPDATEDATE,attr"`
-	PARENTGUID string `xml:"PARENTGUID,attr"`
-	STEADID    string `xml:"STEADID,attr"`
-	PREVID     string `xml:"PREVID,attr"`
-	NEXTID     string `xml:"NEXTID,attr"`
-	OPERSTATUS int    `xml:"OPERSTATUS,attr"`
-	STARTDATE  string `xml:"STARTDATE,attr"`
-	ENDDATE    string `xml:"ENDDATE,attr"`
-	NORMDOC    string `xml:"NORMDOC,attr"`
-	LIVESTATUS int   `xml:"LIVESTATUS,attr"`
-	CADNUM     string `xml:"CADNUM,attr"`
-	DIVTYPE    int    `xml:"DIVTYPE,attr"`
+// publishChanges - события по журналу изменений загруженной и проверенной версии
+func publishChanges(cfg *Config, db *sql.DB, version int) error {
+	if publisher == nil {
+		return nil
+	}
+	total, err := events.PublishChanges(context.Background(), db, publisher, version, cfg.Events.IncludeRecord, slog.Default())
+	if err != nil {
+		return err
+	}
+	slog.Info("Отправка событий закончена", "events", total)
+	return nil
 }
 
-// StructureStatus - Признак строения
-type StructureStatus struct {
-	STRSTATID int    `xml:"STRSTATID,attr"`
-	NAME      string `xml:"NAME,attr"`
-	SHORTNAME string `xml:"SHORTNAME,attr"`
+// refreshDerived - пересчет производных таблиц из derived.tables
+func refreshDerived(cfg *Config, db *sql.DB) error {
+	if len(cfg.Derived) == 0 || !slices.Contains(cfg.Sink.Types, "postgres") {
+		return nil
+	}
+	return loader.RefreshDerived(db, cfg.Derived, slog.Default())
 }
 
-func (pt *PassThru) Read(p []byte) (int, error) {
-	n, err := pt.Reader.Read(p)
-	pt.total += int64(n)
-
-	if err == nil {
-		fmt.Println("Read", n, "bytes for a total of", pt.total)
+// localVersion - VersionId сервиса для локального источника: заданный флагом -version
+// или найденный у сервиса по дате выгрузки; 0, если не удалось определить
+func localVersion(client *source.Client, src *source.Local, version int) int {
+	if version != 0 {
+		return version
 	}
 
-	return n, err
+	version, err := client.VersionByDate(context.Background(), src.Date)
+	if err != nil {
+		slog.Warn("Не удалось определить VersionId выгрузки, укажите -version", "date", src.Date, "err", err)
+		return 0
+	}
+	return version
 }
 
-func checkNewFile(connectionString string) (path string, err error) {
-	url := "http://fias.nalog.ru/WebServices/Public/DownloadService.asmx"
-	var jsonStr = []byte(`<soap:Envelope xmlns:soap="http://www.w3.org/2003/05/soap-envelope" xmlns:dow="http://fias.nalog.ru/WebServices/Public/DownloadService.asmx">
-   <soap:Header/>
-   <soap:Body>
-      <dow:GetLastDownloadFileInfo/>
-   </soap:Body>
-</soap:Envelope>`)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonStr))
-	req.Header.Set("Content-Type", "application/soap+xml; charset=utf-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		log.Println(err)
+// openLocalSource - каталог с файлами локального источника (архив распаковывается в FIAS)
+func openLocalSource(src *source.Local) (string, error) {
+	if src.IsDir {
+		return src.Path, nil
+	}
+	os.RemoveAll("FIAS")
+	if err := UnRar(src.Path); err != nil {
 		return "", err
 	}
-	defer resp.Body.Close()
-
-	body, _ := ioutil.ReadAll(resp.Body)
-	ver := &MyRespEnvelope{}
-	xml.Unmarshal(body, &ver)
-	versionID := ver.Body.GetResponse.GetLastDownloadFileInfoResult.VersionId
+	return "FIAS", nil
+}
 
-	pgDb, err := sql.Open("postgres", connectionString)
+// loadLocalSource - загрузка из уже скачанного архива или распакованного каталога
+func loadLocalSource(cfg *Config, db *sql.DB, client *source.Client, version int) error {
+	src, err := source.OpenLocal(cfg.Source)
 	if err != nil {
-		log.Printf("\nОшибка %s при открытие БД", err)
-		return "", err
+		return err
 	}
-	defer pgDb.Close()
 
-	gq := goqu.New("postgres", pgDb)
-	query, _, _ := gq.From("config").Select("value").Where(goqu.Ex{
-		"id": "TextVersion",
-	}).ToSql()
+	version = localVersion(client, src, version)
+	if version == 0 && (cfg.Diff || cfg.KeepVersions > 0) {
+		return fmt.Errorf("для журнала изменений и снимков нужен VersionId выгрузки от %d", src.Date)
+	}
 
-	var fileVersion string
-	err = pgDb.QueryRow(query).Scan(&fileVersion)
-	switch {
-	case err == sql.ErrNoRows:
-		log.Fatal("Не найдена настройка TextVersion.")
-		return "", err
-	case err != nil:
-		log.Fatal(err)
-		return "", err
-	default:
+	logger := slog.With("version", version, "date", src.Date)
+	logger.Info("Локальный источник", "source", src.Path, "dir", src.IsDir)
+
+	dir, err := openLocalSource(src)
+	if err != nil {
+		return err
 	}
 
-	if fileVersion != string(versionID) {
-		update := gq.From("config").
-			Where(goqu.I("id").Eq("TextVersion")).
-			Update(goqu.Record{"value": versionID})
+	// дата выгрузки не VersionId: без него TextVersion не трогаем, иначе checkNewFile
+	// всегда видел бы новую версию
+	record := loader.RecordLoad
+	if version == 0 {
+		record = loader.RecordHistory
+	}
+	if err := record(db, version, src.Path); err != nil {
+		return err
+	}
 
-		if _, err := update.Exec(); err != nil {
-			log.Printf("\nОшибка %s при записи версии файла", err.Error())
-			return "", err
+	if cfg.canParseFile() {
+		slog.SetDefault(logger)
+		if err := newLoader(cfg, db, version).ParseDir(dir); err != nil {
+			return err
+		}
+		if err := runValidation(cfg, db, version); err != nil {
+			return err
+		}
+		if err := refreshDerived(cfg, db); err != nil {
+			return err
+		}
+		if err := publishChanges(cfg, db, version); err != nil {
+			return err
 		}
-
-		return ver.Body.GetResponse.GetLastDownloadFileInfoResult.FiasCompleteXmlUrl, err
 	}
 
-	return "", err
+	logger.Info("Парсинг закончен")
+	return nil
 }
 
-// DownLoadFile - Грузим файл из ФИАС
-func DownLoadFile(path string) error {
-	if _, err := os.Stat(fileName); !os.IsNotExist(err) {
-		os.RemoveAll(dirName)
-		os.Remove(fileName)
-	}
-
-	output, err := os.Create(fileName)
-	if err != nil {
-		log.Println("Error while creating", "file", "-", err)
-		return err
+// runDiff - сравниваем выгрузку с текущими таблицами и пишем журнал изменений, не заменяя
+// данные: файлы из -source или распакованный каталог dir_name
+func runDiff(cfg *Config, db *sql.DB, client *source.Client, version int) error {
+	var dir string
+	if cfg.Source != "" {
+		src, err := source.OpenLocal(cfg.Source)
+		if err != nil {
+			return err
+		}
+		version = localVersion(client, src, version)
+		if dir, err = openLocalSource(src); err != nil {
+			return err
+		}
+	} else {
+		base, err := filepath.Abs(filepath.Dir(os.Args[0]))
+		if err != nil {
+			return err
+		}
+		dir = base + dirName
+		if version == 0 {
+			if version, err = loadedVersion(db); err != nil {
+				return err
+			}
+		}
 	}
-	defer output.Close()
-
-	response, err := http.Get(path)
-	if err != nil {
-		log.Println("Error while downloading", path, "-", err)
-		return err
+	if version == 0 {
+		return fmt.Errorf("не задана версия выгрузки для журнала изменений, укажите -version")
 	}
-	defer response.Body.Close()
 
-	fileSize := int(response.ContentLength)
+	logger := slog.With("version", version)
+	logger.Info("Сравнение выгрузки с текущими таблицами", "dir", dir)
 
-	bar := pb.New(fileSize).SetUnits(pb.U_BYTES)
-	bar.Start()
-	reader := bar.NewProxyReader(response.Body)
+	pg := loader.NewPostgresSink(db)
+	pg.Diff = true
+	pg.DiffOnly = true
+	l := newLoader(cfg, db, version)
+	l.Sink = pg
+	l.Logger = logger
+	return l.ParseDir(dir)
+}
 
-	_, err := io.Copy(output, reader)
-	if err != nil {
-		log.Println("Error while downloading", path, "-", err)
-		return err
+// loadHouseKinds - признаки владения и строения из справочников для номеров домов;
+// без справочников остаются значения по умолчанию
+func loadHouseKinds(store *query.Store) {
+	if err := store.LoadHouseKinds(context.Background()); err != nil {
+		slog.Warn("Признаки домов по умолчанию", "err", err)
 	}
-
-	return err
 }
 
-// UnRar - распаковываем архив
-func UnRar(fileName string) error {
-	a, err := unarr.NewArchive(fileName)
-	if err != nil {
-		log.Println(err)
-		return err
+// serveAPI - HTTP API запросов к реестру, работает параллельно с загрузкой
+func serveAPI(addr string, db *sql.DB) {
+	store := query.New(db)
+	loadHouseKinds(store)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           api.New(store),
+		ReadHeaderTimeout: 10 * time.Second,
 	}
-	defer a.Close()
 
-	err = a.Extract("FIAS")
-	if err != nil {
-		log.Println(err)
-		return err
+	slog.Info("Запуск API", "listen", addr)
+	if err := server.ListenAndServe(); err != nil {
+		slog.Error("Ошибка API", "listen", addr, "err", err)
+		os.Exit(1)
 	}
-	return err
 }
 
-// Parse - парсер файлов
-func Parse(f string, table string, connectionString string, elementName string, r interface{}) error {
-	log.Printf("Открываем файл %s\n", f)
-
-	file, err := os.Open(f)
+func main() {
+	configPath := flag.String("config", "", "путь к файлу конфига или каталогу с config.toml")
+	rollback := flag.Int("rollback", 0, "вернуть таблицы к сохраненной версии и выйти")
+	listSnapshots := flag.Bool("snapshots", false, "показать сохраненные версии таблиц и выйти")
+	localSource := flag.String("source", "", "локальный архив, распакованный каталог или file:// URL вместо загрузки с сервиса")
+	localSourceVersion := flag.Int("version", 0, "VersionId сервиса для -source и -diff, если его не удается определить")
+	publishVersion := flag.Int("publish", 0, "отправить события по журналу изменений версии и выйти")
+	diffOnly := flag.Bool("diff", false, "записать журнал изменений выгрузки относительно текущих таблиц без их замены и выйти")
+	flag.Parse()
+
+	cfg, err := loadConfig(*configPath)
 	if err != nil {
-		log.Printf("\nОшибка %s открытия файла", err)
-		return err
+		slog.Error("Ошибка в настройках", "err", err)
+		os.Exit(1)
+	}
+	if *localSource != "" {
+		cfg.Source = *localSource
 	}
-	defer file.Close()
 
-	fi, err := file.Stat()
-	if err != nil {
-		log.Printf("\nОшибка %s при получении размера файла", err)
-		return err
+	if err := setupLogger(cfg.LogLevel, cfg.LogFormat); err != nil {
+		slog.Error("Неверные настройки логирования", "err", err)
+		os.Exit(1)
 	}
+	interactive = cfg.Interactive
+	progressInterval = cfg.ProgressInterval
+
+	dirName = cfg.DirName
+	fileName = cfg.FileName
+	dbinfo := cfg.ConnectionString()
 
-	pgDb, err := sql.Open("postgres", connectionString)
+	client, err := source.NewClient(cfg.Service)
 	if err != nil {
-		log.Printf("\nОшибка %s при открытие БД", err)
-		return err
+		slog.Error("Ошибка в настройках сервиса", "err", err)
+		os.Exit(1)
 	}
-	defer pgDb.Close()
+	client.Progress = newProgressReader
 
-	gq := goqu.New("postgres", pgDb)
-
-	tempTableName := table
-	if fi.Size() > int64(20388921) {
-		tempTableName = "temp_" + table
-		log.Printf("\nБольшой файл, создаем временную таблицу %s\n", tempTableName)
-		createTemplateTable := fmt.Sprintf("CREATE TABLE %s ( like %s including all);", tempTableName, table)
+	db, err := sql.Open("postgres", dbinfo)
+	if err != nil {
+		slog.Error("Ошибка при открытии БД", "err", err)
+		os.Exit(1)
+	}
+	defer db.Close()
 
-		_, err = pgDb.Exec(createTemplateTable)
+	if *listSnapshots {
+		snapshots, err := loader.ListSnapshots(db)
 		if err != nil {
-			log.Printf("Ошибка %s при создании временной таблицы", err)
-			return err
+			slog.Error("Ошибка чтения снимков", "err", err)
+			os.Exit(1)
+		}
+		for _, s := range snapshots {
+			fmt.Printf("%d\t%s\t%s\t%s\n", s.Version, s.Table, s.Name, s.CreatedAt.Format(time.RFC3339))
 		}
-		defer pgDb.Exec("DROP TABLE " + tempTableName + ";")
+		return
 	}
 
-	if tempTableName == table {
-		result, err := pgDb.Exec("TRUNCATE " + tempTableName + ";")
+	if *rollback != 0 {
+		tables, err := loader.Rollback(db, *rollback)
 		if err != nil {
-			log.Printf("\nОшибка %s при удалении данных из таблице", err)
-			return err
+			slog.Error("Ошибка отката", "version", *rollback, "err", err)
+			os.Exit(1)
+		}
+		// TextVersion остается последней версией сервиса, иначе checkNewFile снова
+		// скачал бы отмененную выгрузку
+		if err := loader.RecordHistory(db, *rollback, "rollback"); err != nil {
+			slog.Error("Ошибка при записи версии", "version", *rollback, "err", err)
+			os.Exit(1)
 		}
-		rowsAffected, _ := result.RowsAffected()
-		log.Printf("\nУдалено %v из таблицы %s\n", rowsAffected, tempTableName)
+		slog.Info("Откат выполнен", "version", *rollback, "tables", tables)
+		return
 	}
 
-	s := reflect.ValueOf(r).Elem()
-	columnsCount := s.NumField()
-	columnsName := make([]string, columnsCount)
-
-	for ii := 0; ii < columnsCount; ii++ {
-		columnsName[ii] = strings.ToLower(s.Type().Field(ii).Name)
+	if *diffOnly {
+		if err := runDiff(cfg, db, client, *localSourceVersion); err != nil {
+			slog.Error("Ошибка сравнения версий", "err", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	var inElement string
-	bar := pb.New(int(fi.Size())).SetUnits(pb.U_BYTES)
-	bar.Start()
-	reader := bar.NewProxyReader(file)
-	decoder := xml.NewDecoder(reader)
-	arguments := []goqu.Record{}
-	var total int64
-	for {
-		// Read tokens from the XML document in a stream.
-		t, _ := decoder.Token()
-		if t == nil {
-			break
+	if cfg.Events.Enabled {
+		publisher, err = newPublisher(cfg.Events)
+		if err != nil {
+			slog.Error("Ошибка подключения к брокеру событий", "broker", cfg.Events.Broker, "err", err)
+			os.Exit(1)
 		}
+		defer publisher.Close()
+	}
 
-		// Inspect the type of the token just read.
-		switch se := t.(type) {
-		case xml.StartElement:
-			// If we just read a StartElement token
-			inElement = se.Name.Local
-			// ...and its name is "page"
-			if inElement == elementName {
-				err := decoder.DecodeElement(&r, &se)
-				if err != nil {
-					log.Printf("\nОшибка при декодинге %s, элемент - %s ", err, elementName)
-					return err
-				}
-				argument := make(goqu.Record)
-				for j := 0; j < columnsCount; j++ {
-					f := s.Field(j)
-					argument[columnsName[j]] = f.Interface()
-				}
-				arguments = append(arguments, argument)
-				total++
-			}
-
-		default:
+	if *publishVersion != 0 {
+		if publisher == nil {
+			slog.Error("События выключены, задайте events.enabled")
+			os.Exit(1)
 		}
-
-		if len(arguments) == 5000 {
-			if _, err := gq.From(tempTableName).Insert(arguments).Exec(); err != nil {
-				log.Println(err.Error())
-				return err
-			}
-			arguments = []goqu.Record{}
+		if err := publishChanges(cfg, db, *publishVersion); err != nil {
+			slog.Error("Ошибка отправки событий", "version", *publishVersion, "err", err)
+			os.Exit(1)
 		}
+		return
 	}
 
-	if len(arguments) > 0 {
-		if _, err := gq.From(tempTableName).Insert(arguments).Exec(); err != nil {
-			log.Println(err.Error())
-			return err
-		}
+	slog.Info("Запуск", "db", redactDSN(dbinfo), "work_regime", cfg.WorkRegime, "interactive", interactive)
 
-		if tempTableName != table {
-			log.Printf("\nНачинаем переносить данные\n")
-			_, err := pgDb.Exec("DROP TABLE " + table + "; ALTER TABLE " + tempTableName + " RENAME TO " + table + ";")
-			if err != nil {
-				log.Println(err)
-				return err
-			}
-			fmt.Printf("\nТаблица скопирована\n")
+	if cfg.Source != "" {
+		if err := loadLocalSource(cfg, db, client, *localSourceVersion); err != nil {
+			slog.Error("Ошибка загрузки из локального источника", "source", cfg.Source, "err", err)
+			os.Exit(1)
 		}
-		arguments = []goqu.Record{}
+		return
 	}
 
-	fmt.Println()
+	for name, t := range cfg.FormatTemplates {
+		query.Templates[name] = t
+	}
+	if cfg.APIListen != "" {
+		go serveAPI(cfg.APIListen, db)
+	}
 
-	return err
-}
+	baseLogger := slog.Default()
+	for {
+		var check string
+		var version int
+		logger := baseLogger
+		if cfg.canCheckNewFile() {
+			check, version, err = checkNewFile(client, db)
+			if err != nil {
+				slog.Error("Ошибка проверки новых файлов", "err", err)
+				break
+			}
 
-func main() {
-	viper.SetConfigName("config")
-	viper.AddConfigPath(".")
-	err := viper.ReadInConfig()
-	if err != nil {
-		log.Printf("Ошибка %s чтения конфига", err)
-	} else {
-		server := viper.GetString("datebase.server")
-		port := viper.GetInt("datebase.port")
-		user := viper.GetString("datebase.user")
-		password := viper.GetString("datebase.password")
-		base := viper.GetString("datebase.base")
-		dirName = viper.GetString("config.dir_name")
-		workRegime := viper.GetString("config.work_regime")
-		canCheckNewFile := workRegime[0:1]
-		canDownloadFile := workRegime[1:2]
-		canUnrarFile := workRegime[2:3]
-		canParseFile := workRegime[3:4]
-		fileName = viper.GetString("config.file_name")
-		dbinfo := fmt.Sprintf("host=%s port=%v user=%s password=%s dbname=%s sslmode=disable application_name='FIAS Parser'",
-			server, port, user, password, base)
-
-		fmt.Println(dbinfo)
-		for {
-			var check string
-			if canCheckNewFile == "1"{
-				check, err = checkNewFile(dbinfo)
-				if err != nil {
-					log.Println(err)
-					break
-				}			
-
-				if check == "" {
-					log.Println("Нет новых файлов")
-					time.Sleep(24 * time.Hour)
-					break
-				}
+			if check == "" {
+				slog.Info("Нет новых файлов", "version", version)
+				time.Sleep(24 * time.Hour)
+				break
+			}
+			logger = logger.With("version", version)
+		} else {
+			version, err = loadedVersion(db)
+			if err != nil {
+				slog.Error("Ошибка чтения настройки TextVersion", "err", err)
+				break
 			}
+			logger = logger.With("version", version)
+		}
 
-			if canDownloadFile == "1" && canCheckNewFile == "1"{
-				err := DownLoadFile(check)
+		if cfg.canDownloadFile() && cfg.canCheckNewFile() {
+			client.Logger = logger
+			err := DownLoadFile(client, check)
 
-				if err != nil {
-					log.Printf("Ошибка %s при загрузке файла", err)
-					break
-				}
+			if err != nil {
+				logger.Error("Ошибка при загрузке файла", "err", err)
+				break
 			}
+		}
 
-			if canUnrarFile == "1"{
-				err = UnRar(fileName)
-				if err != nil {
-					log.Printf("Ошибка %s при распаковки файла", err)
-					break
-				}
+		if cfg.canUnrarFile() {
+			err = UnRar(fileName)
+			if err != nil {
+				logger.Error("Ошибка при распаковке файла", "err", err)
+				break
 			}
+		}
 
+		if cfg.canParseFile() {
 			dir, err := filepath.Abs(filepath.Dir(os.Args[0]))
 			if err != nil {
-				log.Fatal(err)
+				logger.Error("Ошибка определения каталога", "err", err)
+				os.Exit(1)
 			}
-			dir += dirName
 
-			files, err := ioutil.ReadDir("FIAS")
-			if err != nil {
-				log.Fatal(err)
+			slog.SetDefault(logger)
+			if err := newLoader(cfg, db, version).ParseDir(dir + dirName); err != nil {
+				logger.Error("Ошибка загрузки", "dir", dir+dirName, "err", err)
+				os.Exit(1)
+			}
+			if err := runValidation(cfg, db, version); err != nil {
+				logger.Error("Загрузка не прошла проверку", "err", err)
+				os.Exit(1)
+			}
+			if err := refreshDerived(cfg, db); err != nil {
+				logger.Error("Ошибка пересчета производных таблиц", "err", err)
+				os.Exit(1)
+			}
+			if err := publishChanges(cfg, db, version); err != nil {
+				logger.Error("Ошибка отправки событий, повторить: -publish <версия>", "err", err)
+				os.Exit(1)
 			}
-			if canParseFile == "1"{
-				for _, file := range files {
-					switch {
-					case ACTSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("ACTSTAT_PATTERN")
-						r := new(ActualStatus)
-						Parse(dir+file.Name(), "actual_status", dbinfo, "ActualStatus", r)
-					case ADDROBJ_PATTERN.MatchString(file.Name()):
-						fmt.Println("ADDROBJ_PATTERN")
-						r := new(Object)
-						Parse(dir+file.Name(), "address_objects", dbinfo, "Object", r)
-					case CENTERST_PATTERN.MatchString(file.Name()):
-						fmt.Println("ADDROBJ_PATTERN")
-						r := new(CenterStatus)
-						Parse(dir+file.Name(), "center_status", dbinfo, "CenterStatus", r)
-					case CURENTST_PATTERN.MatchString(file.Name()):
-						fmt.Println("CURENTST_PATTERN")
-						r := new(CenterStatus)
-						Parse(dir+file.Name(), "current_status", dbinfo, "CurrentStatus", r)
-					case DEL_ADDROBJ_PATTERN.MatchString(file.Name()):
-						fmt.Println("DEL_ADDROBJ_PATTERN")
-						r := new(Object)
-						Parse(dir+file.Name(), "del_address_objects", dbinfo, "Object", r)
-					case DEL_HOUSE_PATTERN.MatchString(file.Name()):
-						fmt.Println("DEL_HOUSE_PATTERN")
-						r := new(House)
-						Parse(dir+file.Name(), "del_house", dbinfo, "House", r)
-					case DEL_HOUSEINT_PATTERN.MatchString(file.Name()):
-						fmt.Println("DEL_HOUSEINT_PATTERN")
-						r := new(HouseInterval)
-						Parse(dir+file.Name(), "del_house_interval", dbinfo, "HouseInterval", r)
-					case DEL_NORMDOC_PATTERN.MatchString(file.Name()):
-						fmt.Println("DEL_NORMDOC_PATTERN")
-						r := new(NormativeDocument)
-						Parse(dir+file.Name(), "del_normative_document", dbinfo, "NormativeDocument", r)
-					case ESTSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("ESTSTAT_PATTERN")
-						r := new(EstateStatus)
-						Parse(dir+file.Name(), "estate_status", dbinfo, "EstateStatus", r)
-					case HOUSE_PATTERN.MatchString(file.Name()):
-						fmt.Println("HOUSE_PATTERN")
-						r := new(House)
-						Parse(dir+file.Name(), "house", dbinfo, "House", r)
-					case HOUSEINT_PATTERN.MatchString(file.Name()):
-						fmt.Println("HOUSEINT_PATTERN")
-						r := new(HouseInterval)
-						Parse(dir+file.Name(), "house_interval", dbinfo, "HouseInterval", r)
-					case HSTSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("HSTSTAT_PATTERN")
-						r := new(HouseStateStatus)
-						Parse(dir+file.Name(), "house_state_status", dbinfo, "HouseStateStatus", r)
-					case INTVSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("INTVSTAT_PATTERN")
-						r := new(IntervalStatus)
-						Parse(dir+file.Name(), "interval_status", dbinfo, "IntervalStatus", r)
-					case LANDMARK_PATTERN.MatchString(file.Name()):
-						fmt.Println("LANDMARK_PATTERN")
-						r := new(Landmark)
-						Parse(dir+file.Name(), "landmark", dbinfo, "Landmark", r)
-					case NDOCTYPE_PATTERN.MatchString(file.Name()):
-						fmt.Println("NDOCTYPE_PATTERN")
-						r := new(NormativeDocumentType)
-						Parse(dir+file.Name(), "normative_document_type", dbinfo, "NormativeDocumentType", r)
-					case NORMDOC_PATTERN.MatchString(file.Name()):
-						fmt.Println("NORMDOC_PATTERN")
-						r := new(NormativeDocumentType)
-						Parse(dir+file.Name(), "normative_document", dbinfo, "NormativeDocument", r)
-					case OPERSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("OPERSTAT_PATTERN")
-						r := new(OperationStatus)
-						Parse(dir+file.Name(), "operation_status", dbinfo, "OperationStatus", r)
-					case SOCRBASE_PATTERN.MatchString(file.Name()):
-						fmt.Println("SOCRBASE_PATTERN")
-						r := new(AddressObjectType)
-						Parse(dir+file.Name(), "address_object_type", dbinfo, "AddressObjectType", r)
-					case STRSTAT_PATTERN.MatchString(file.Name()):
-						fmt.Println("STRSTAT_PATTERN")
-						r := new(StructureStatus)
-						Parse(dir+file.Name(), "structure_status", dbinfo, "StructureStatus", r)
-					case STEAD_PATTERN.MatchString(file.Name()):
-						fmt.Println("STEAD_PATTERN")
-						r := new(Stead)
-						Parse(dir+file.Name(), "steads", dbinfo, "Stead", r)
-					case ROOM_PATTERN.MatchString(file.Name()):
-						fmt.Println("ROOM_PATTERN")
-						r := new(Room)
-						Parse(dir+file.Name(), "rooms", dbinfo, "Room", r)
-					default:
-						fmt.Println("It doesn't match")
-					}
-				}
+			if cfg.APIListen != "" {
+				loadHouseKinds(query.New(db))
 			}
-			log.Printf("\nПарсинг закончен, ждем неделю\n")
-			time.Sleep(150 * time.Hour)
 		}
+		logger.Info("Парсинг закончен, ждем неделю")
+		time.Sleep(150 * time.Hour)
 	}
 }
@@ -0,0 +1,8 @@
+-- Индексы для поиска по кадастровому номеру (query.ByCadastral, GET /v1/cadastral/{number}).
+-- Выполнить один раз; при загрузке через временные таблицы индексы копируются (like ... including all).
+
+CREATE INDEX IF NOT EXISTS house_cadnum_idx ON house (cadnum) WHERE cadnum <> '';
+CREATE INDEX IF NOT EXISTS rooms_cadnum_idx ON rooms (cadnum) WHERE cadnum <> '';
+CREATE INDEX IF NOT EXISTS rooms_roomcadnum_idx ON rooms (roomcadnum) WHERE roomcadnum <> '';
+CREATE INDEX IF NOT EXISTS steads_cadnum_idx ON steads (cadnum) WHERE cadnum <> '';
+CREATE INDEX IF NOT EXISTS landmark_cadnum_idx ON landmark (cadnum) WHERE cadnum <> '';
@@ -0,0 +1,16 @@
+-- Справочники типов помещений (AS_FLATTYPE) и комнат (AS_ROOMTYPE).
+-- Выполнить один раз перед загрузкой: таблицы справочников очищаются и заполняются загрузчиком.
+
+CREATE TABLE IF NOT EXISTS flat_type (
+	fltypeid integer PRIMARY KEY,
+	name text NOT NULL,
+	shortname text
+);
+
+CREATE TABLE IF NOT EXISTS room_type (
+	rmtypeid integer PRIMARY KEY,
+	name text NOT NULL,
+	shortname text
+);
+
+CREATE INDEX IF NOT EXISTS rooms_houseguid_idx ON rooms (houseguid);
@@ -0,0 +1,16 @@
+-- Индексы для запросов адресов на дату (пакет query, HTTP API): поиск по GUID, по дому и улице,
+-- переход по версиям AOID и поиск по наименованию без учета регистра (FindAt).
+-- Выполнить один раз; при загрузке через временные таблицы индексы копируются (like ... including all).
+
+CREATE INDEX IF NOT EXISTS address_objects_aoguid_idx ON address_objects (aoguid, startdate);
+CREATE INDEX IF NOT EXISTS address_objects_aoid_idx ON address_objects (aoid);
+CREATE INDEX IF NOT EXISTS address_objects_formalname_idx ON address_objects (lower(formalname));
+
+CREATE INDEX IF NOT EXISTS house_houseguid_idx ON house (houseguid, startdate);
+CREATE INDEX IF NOT EXISTS house_aoguid_idx ON house (aoguid);
+CREATE INDEX IF NOT EXISTS house_interval_aoguid_idx ON house_interval (aoguid);
+
+CREATE INDEX IF NOT EXISTS steads_steadguid_idx ON steads (steadguid, startdate);
+
+CREATE INDEX IF NOT EXISTS rooms_roomguid_idx ON rooms (roomguid, startdate);
+CREATE INDEX IF NOT EXISTS rooms_houseguid_idx ON rooms (houseguid);
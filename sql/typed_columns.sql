@@ -0,0 +1,65 @@
+-- Перевод текстовых колонок дат, почтовых индексов и LIVESTATUS в типизированные.
+-- Пустые строки становятся NULL. Выполнить один раз перед загрузкой новой версией парсера.
+
+ALTER TABLE address_objects
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer,
+	ALTER COLUMN livestatus TYPE integer USING nullif(livestatus::text, '')::integer;
+
+ALTER TABLE del_address_objects
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer,
+	ALTER COLUMN livestatus TYPE integer USING nullif(livestatus::text, '')::integer;
+
+ALTER TABLE house
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE del_house
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE house_interval
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE del_house_interval
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE landmark
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE steads
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer;
+
+ALTER TABLE rooms
+	ALTER COLUMN updatedate TYPE date USING nullif(updatedate::text, '')::date,
+	ALTER COLUMN startdate TYPE date USING nullif(startdate::text, '')::date,
+	ALTER COLUMN enddate TYPE date USING nullif(enddate::text, '')::date,
+	ALTER COLUMN postalcode TYPE integer USING nullif(postalcode::text, '')::integer,
+	ALTER COLUMN livestatus TYPE integer USING nullif(livestatus::text, '')::integer;
+
+ALTER TABLE normative_document
+	ALTER COLUMN docdate TYPE date USING nullif(docdate::text, '')::date;
+
+ALTER TABLE del_normative_document
+	ALTER COLUMN docdate TYPE date USING nullif(docdate::text, '')::date;